@@ -0,0 +1,84 @@
+package errx
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Adapter teaches Frames and the kind helpers how to walk a foreign error type that records more than the standard Unwrap conventions expose.
+// It returns false for errors it does not recognize.
+type Adapter func(err error) (Adapted, bool)
+
+// Adapted describes a foreign error recognized by an Adapter.
+type Adapted struct {
+	// Cause is the error wrapped by the foreign error. It is only used when the error does not implement Unwrap.
+	Cause error
+	// Errors holds the errors combined by the foreign error, which are walked as joined errors.
+	Errors []error
+	// Stack is the call stack recorded by the foreign error.
+	Stack []runtime.Frame
+}
+
+var (
+	_adaptersMu sync.Mutex
+	_adapters   atomic.Pointer[[]Adapter]
+)
+
+// RegisterAdapter adds an adapter consulted for every foreign error walked. Adapters are tried in the order they were registered.
+// It should be called once per adapter at startup.
+func RegisterAdapter(adapter Adapter) {
+	_adaptersMu.Lock()
+	defer _adaptersMu.Unlock()
+
+	var adapters []Adapter
+	if curr := _adapters.Load(); curr != nil {
+		adapters = append(adapters, *curr...)
+	}
+	adapters = append(adapters, adapter)
+	_adapters.Store(&adapters)
+}
+
+// adapt runs the registered adapters on a foreign error.
+func adapt(err error) (Adapted, bool) {
+	if adapters := _adapters.Load(); adapters != nil {
+		for _, adapter := range *adapters {
+			if adapted, ok := adapter(err); ok {
+				return adapted, true
+			}
+		}
+	}
+	return Adapted{}, false
+}
+
+// nextErrors returns the errors a foreign error wraps: a single cause for linear chains or every joined error.
+// adapted is the result of running the adapters on err.
+func nextErrors(err error, adapted Adapted) (cause error, joined []error) {
+	if len(adapted.Errors) > 0 {
+		return nil, adapted.Errors
+	} else if uw, ok := err.(interface{ Unwrap() []error }); ok {
+		return nil, uw.Unwrap()
+	} else if cause = Unwrap(err); cause != nil {
+		return cause, nil
+	}
+	return adapted.Cause, nil
+}
+
+// findErr walks the error chain depth first through joined and adapted errors and reports whether match returned true for any error.
+func findErr(err error, match func(error) bool) bool {
+	for err != nil {
+		if match(err) {
+			return true
+		}
+
+		adapted, _ := adapt(err)
+		cause, joined := nextErrors(err, adapted)
+		for _, e := range joined {
+			if findErr(e, match) {
+				return true
+			}
+		}
+		err = cause
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package errx
+
+import (
+	"errors"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// legacyErr wraps its cause with Cause only, like older error libraries
+type legacyErr struct {
+	msg   string
+	cause error
+	stack []runtime.Frame
+}
+
+func (e *legacyErr) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+func (e *legacyErr) Cause() error { return e.cause }
+
+// multiErr combines errors without implementing Unwrap() []error
+type multiErr struct {
+	errs []error
+}
+
+func (e *multiErr) Error() string { return "multiple errors" }
+
+func init() {
+	RegisterAdapter(func(err error) (Adapted, bool) {
+		switch e := err.(type) {
+		case *legacyErr:
+			return Adapted{Cause: e.cause, Stack: e.stack}, true
+		case *stackOnly:
+			return Adapted{Cause: e.cause, Stack: e.stack}, true
+		case *multiErr:
+			return Adapted{Errors: e.errs}, true
+		}
+		return Adapted{}, false
+	})
+}
+
+func testStack() []runtime.Frame {
+	return []runtime.Frame{
+		{Function: "app/users.Find", File: "/src/app/users/find.go", Line: 42},
+		{Function: "app/http.Handle", File: "/src/app/http/handle.go", Line: 7},
+	}
+}
+
+func TestAdapter(t *testing.T) {
+	t.Run("Cause and stack", func(t *testing.T) {
+		err := Wrap(3, &legacyErr{msg: "lookup", stack: testStack(), cause: NewKind(1, DefineKind("notfound"), "user missing")})
+
+		frames := Frames(err)
+		assert.Len(t, frames, 3)
+		assert.Equal(t, "lookup:", frames[1].Msg)
+		assert.Equal(t, "lookup: (at app/users.Find find.go:42)", frames[1].String())
+		assert.Equal(t, []string{"app/users.Find /src/app/users/find.go:42", "app/http.Handle /src/app/http/handle.go:7"}, frames[1].StackTrace())
+
+		assert.Equal(t, []int{3, 1}, err.(*errx).Stamps())
+		assert.True(t, IsKind(err, DefineKind("notfound")))
+		assert.Contains(t, Report(err, Indent), "  lookup: (at app/users.Find find.go:42)")
+
+		cause := attrMap(attrMap(err.(slog.LogValuer).LogValue().Group())["error_cause"].Group())
+		assert.Equal(t, frames[1].StackTrace(), cause["error_stack"].Any())
+	})
+
+	t.Run("Stack only frames are merged", func(t *testing.T) {
+		err := &stackOnly{stack: testStack(), cause: &legacyErr{msg: "lookup", cause: errors.New("io failure")}}
+
+		frames := Frames(err)
+		assert.Len(t, frames, 2)
+		assert.Equal(t, "lookup:", frames[0].Msg)
+		assert.Equal(t, testStack(), frames[0].Stack)
+		assert.Equal(t, "io failure", frames[1].Msg)
+		assert.Empty(t, frames[1].Stack)
+	})
+
+	t.Run("Combined errors are joined", func(t *testing.T) {
+		data := DataKind[int]("user_id")
+		err := Wrap(10, &multiErr{errs: []error{New(1, "e1"), WrapKind(2, data(42), errors.New("e2"))}})
+
+		assert.Equal(t, []int{10, 1, 2}, err.(*errx).Stamps())
+		assert.Equal(t, [][]int{{10, 1}, {10, 2}}, StampPaths(err))
+		assert.True(t, IsKind(err, DefineKind("user_id")))
+		assert.True(t, IsDataKind(err, data))
+
+		v, ok := FindData(err, data)
+		assert.True(t, ok)
+		assert.Equal(t, 42, *v)
+		assert.Equal(t, "[ts 1] e1; [ts 10]\ne2; [ts 2 kind user_id data 42]; [ts 10]", Report(err, Reversed))
+	})
+
+	t.Run("Standard joins", func(t *testing.T) {
+		err := Join(errors.New("plain"), NewKind(1, DefineKind("conflict"), "e1"))
+		assert.True(t, IsKind(err, DefineKind("conflict")))
+		assert.False(t, IsKind(err, DefineKind("notfound")))
+	})
+}
+
+// stackOnly renders exactly the message of the error it wraps, like pkg/errors' withStack
+type stackOnly struct {
+	cause error
+	stack []runtime.Frame
+}
+
+func (e *stackOnly) Error() string { return e.cause.Error() }
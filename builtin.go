@@ -0,0 +1,30 @@
+package errx
+
+// RetryAfter marks an error as retryable after the given number of seconds.
+// Retry helpers wait at least this long before the next attempt.
+var RetryAfter = DataKind[float64]("retry_after", KindMeta{Retryable: true, HTTPStatus: 503, GRPCCode: 14, Severity: SeverityWarning})
+
+// Panicked marks errors converted from panics by Recover and Safe.
+// Its data is the formatted panic value when the value is not an error.
+var Panicked = DataKind[string]("panic", KindMeta{HTTPStatus: 500, GRPCCode: 13, Severity: SeverityCritical})
+
+// panicKind is Panicked without data. It marks errors converted from panics whose value is an error that is already part of the chain
+var panicKind = Kind{kind: Panicked("").kind}
+
+// Built-in kinds assigned to standard library errors once enabled with ClassifyStdErrors.
+var (
+	// Canceled marks operations canceled by the caller, such as context.Canceled
+	Canceled = DefineKind("canceled", KindMeta{HTTPStatus: 499, GRPCCode: 1, Severity: SeverityInfo, Description: "The request was canceled"})
+	// Timeout marks operations that ran out of time, such as context.DeadlineExceeded and net.Error timeouts
+	Timeout = DefineKind("timeout", KindMeta{HTTPStatus: 504, GRPCCode: 4, Retryable: true, Severity: SeverityWarning, Description: "The request timed out"})
+	// NotFound marks missing resources, such as fs.ErrNotExist, or sql.ErrNoRows with errxsql
+	NotFound = DefineKind("notfound", KindMeta{HTTPStatus: 404, GRPCCode: 5, Severity: SeverityInfo, Description: "The resource does not exist"})
+	// Permission marks denied access, such as fs.ErrPermission
+	Permission = DefineKind("permission", KindMeta{HTTPStatus: 403, GRPCCode: 7, Severity: SeverityWarning, Description: "Access to the resource is denied"})
+	// Exists marks resources that already exist, such as fs.ErrExist
+	Exists = DefineKind("exists", KindMeta{HTTPStatus: 409, GRPCCode: 6, Severity: SeverityInfo, Description: "The resource already exists"})
+	// Invalid marks invalid arguments, such as fs.ErrInvalid
+	Invalid = DefineKind("invalid", KindMeta{HTTPStatus: 400, GRPCCode: 3, Severity: SeverityInfo, Description: "The request is invalid"})
+	// Unavailable marks broken connections and streams, such as io.ErrUnexpectedEOF
+	Unavailable = DefineKind("unavailable", KindMeta{HTTPStatus: 503, GRPCCode: 14, Retryable: true, Severity: SeverityWarning, Description: "The service is unavailable"})
+)
@@ -0,0 +1,101 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+var _classifyStd atomic.Bool
+
+// ClassifyStdErrors enables tagging standard library errors with the built-in kinds, such as Timeout for context.DeadlineExceeded or NotFound for fs.ErrNotExist.
+// Errors of other packages are classified by classifiers added with RegisterClassifier, such as errxsql for sql.ErrNoRows.
+// Wrap and Wrapf tag the frame wrapping a foreign error that has no kind of its own, and IsKind recognizes classified errors at any layer of the chain,
+// including below frames whose kind was set explicitly with WrapKind.
+func ClassifyStdErrors(enabled bool) {
+	_classifyStd.Store(enabled)
+}
+
+// Classifier returns the built-in kind of an error it recognizes. It returns false for errors it does not recognize.
+type Classifier func(err error) (Kind, bool)
+
+var (
+	_classifiersMu sync.Mutex
+	_classifiers   atomic.Pointer[[]Classifier]
+)
+
+// RegisterClassifier adds a classifier consulted after the standard library checks once ClassifyStdErrors is enabled.
+// Classifiers are tried in the order they were registered. It should be called once per classifier at startup.
+func RegisterClassifier(classifier Classifier) {
+	_classifiersMu.Lock()
+	defer _classifiersMu.Unlock()
+
+	var classifiers []Classifier
+	if curr := _classifiers.Load(); curr != nil {
+		classifiers = append(classifiers, *curr...)
+	}
+	classifiers = append(classifiers, classifier)
+	_classifiers.Store(&classifiers)
+}
+
+// classify returns the built-in kind of a standard library error or of an error recognized by a registered classifier.
+// Errors combining several errors are not classified since their branches may disagree. IsKind still classifies each branch.
+func classify(err error) (Kind, bool) {
+	switch {
+	case joinsErrors(err):
+		return Kind{}, false
+	case errors.Is(err, context.Canceled):
+		return Canceled, true
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded), isTimeout(err):
+		return Timeout, true
+	case errors.Is(err, fs.ErrNotExist):
+		return NotFound, true
+	case errors.Is(err, fs.ErrPermission):
+		return Permission, true
+	case errors.Is(err, fs.ErrExist):
+		return Exists, true
+	case errors.Is(err, fs.ErrInvalid):
+		return Invalid, true
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.ErrClosedPipe):
+		return Unavailable, true
+	}
+	if classifiers := _classifiers.Load(); classifiers != nil {
+		for _, classifier := range *classifiers {
+			if kind, ok := classifier(err); ok {
+				return kind, true
+			}
+		}
+	}
+	return Kind{}, false
+}
+
+// joinsErrors reports whether err or an error it wraps combines several errors with Unwrap() []error.
+func joinsErrors(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isTimeout reports whether the error reports a timeout the way net.Error does.
+func isTimeout(err error) bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// classified tags a frame wrapping a foreign error with the built-in kind of the error when classification is enabled.
+func classified(e *errx) *errx {
+	if e.err == nil || e.kind.kind != "" || !_classifyStd.Load() {
+		return e
+	}
+	if kind, ok := classify(e.err); ok {
+		e.kind = kind
+	}
+	return e
+}
@@ -0,0 +1,107 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type netTimeout struct{}
+
+func (netTimeout) Error() string   { return "i/o timeout" }
+func (netTimeout) Timeout() bool   { return true }
+func (netTimeout) Temporary() bool { return true }
+
+func TestClassifyStdErrors(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		err := Wrap(1, context.DeadlineExceeded)
+		assert.Equal(t, "", err.(*errx).Kind())
+		assert.False(t, IsKind(err, Timeout))
+	})
+
+	ClassifyStdErrors(true)
+	defer ClassifyStdErrors(false)
+
+	t.Run("Built-in kinds", func(t *testing.T) {
+		cases := []struct {
+			err  error
+			kind Kind
+		}{
+			{context.Canceled, Canceled},
+			{context.DeadlineExceeded, Timeout},
+			{os.ErrDeadlineExceeded, Timeout},
+			{netTimeout{}, Timeout},
+			{fs.ErrNotExist, NotFound},
+			{fs.ErrPermission, Permission},
+			{fs.ErrExist, Exists},
+			{fs.ErrInvalid, Invalid},
+			{io.ErrUnexpectedEOF, Unavailable},
+		}
+		for _, c := range cases {
+			err := Wrap(1, fmt.Errorf("query: %w", c.err))
+			assert.Equal(t, c.kind.Name(), err.(*errx).Kind(), c.err.Error())
+			assert.True(t, IsKind(err, c.kind), c.err.Error())
+		}
+	})
+
+	t.Run("Wrapped stdlib errors", func(t *testing.T) {
+		_, openErr := os.Open("/does/not/exist")
+		err := Wrapf(2, "load config: %w", openErr)
+		assert.True(t, IsKind(err, NotFound))
+		assert.Contains(t, err.Error(), "[ts 2 kind notfound]; load config: open /does/not/exist")
+
+		meta, ok := KindInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, 404, meta.HTTPStatus)
+	})
+
+	t.Run("Explicit kinds win", func(t *testing.T) {
+		dbErr := DefineKind("classify_db")
+		err := WrapKind(1, dbErr, context.DeadlineExceeded)
+		assert.Equal(t, "classify_db", err.(*errx).Kind())
+		assert.True(t, IsKind(err, dbErr))
+		assert.True(t, IsKind(err, Timeout))
+	})
+
+	t.Run("Any layer", func(t *testing.T) {
+		err := Wrap(3, Wrap(2, NewKind(1, DefineKind("classify_query"), "query failed")))
+		assert.False(t, IsKind(err, Timeout))
+
+		err = Wrap(3, Wrap(2, WrapKind(1, DefineKind("classify_query"), fmt.Errorf("driver: %w", context.DeadlineExceeded))))
+		assert.Equal(t, "", err.(*errx).Kind())
+		assert.True(t, IsKind(err, Timeout))
+		assert.False(t, IsKind(err, Canceled))
+	})
+
+	t.Run("Joined errors", func(t *testing.T) {
+		err := Wrap(1, errors.Join(context.DeadlineExceeded, errors.New("cache miss")))
+		assert.Equal(t, "", err.(*errx).Kind())
+		assert.True(t, IsKind(err, Timeout))
+
+		err = Wrapf(2, "sync: %w", fmt.Errorf("batch: %w", errors.Join(fs.ErrNotExist, fs.ErrPermission)))
+		assert.Equal(t, "", err.(*errx).Kind())
+		assert.True(t, IsKind(err, NotFound))
+		assert.True(t, IsKind(err, Permission))
+		assert.False(t, IsKind(err, Timeout))
+	})
+
+	t.Run("Registered classifiers", func(t *testing.T) {
+		errQuota := errors.New("quota exceeded")
+		RegisterClassifier(func(err error) (Kind, bool) {
+			return Unavailable, errors.Is(err, errQuota)
+		})
+		assert.True(t, IsKind(Wrap(1, fmt.Errorf("upload: %w", errQuota)), Unavailable))
+	})
+
+	t.Run("Unknown errors", func(t *testing.T) {
+		err := Wrap(1, errors.New("plain"))
+		assert.Equal(t, "", err.(*errx).Kind())
+		assert.False(t, IsKind(err, NotFound))
+	})
+}
@@ -0,0 +1,555 @@
+package main
+
+import (
+	"cmp"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const errxPath = "github.com/michaelolof/errx"
+
+// Catalog lists every declared kind and every stamped call site found in a module.
+type Catalog struct {
+	Kinds  []KindEntry  `json:"kinds"`
+	Stamps []StampEntry `json:"stamps"`
+	// Errors lists the files that could not be parsed and were skipped.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// KindEntry describes a declared error kind.
+type KindEntry struct {
+	Name        string  `json:"name"`
+	Var         string  `json:"var,omitempty"`
+	DataType    string  `json:"data_type,omitempty"`
+	Sensitive   bool    `json:"sensitive,omitempty"`
+	HTTPStatus  int     `json:"http_status,omitempty"`
+	GRPCCode    int     `json:"grpc_code,omitempty"`
+	Retryable   bool    `json:"retryable,omitempty"`
+	Severity    string  `json:"severity,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Location    string  `json:"location"`
+	Stamps      []int64 `json:"stamps,omitempty"`
+}
+
+// StampEntry describes a call site raising or wrapping an error with a stamp.
+type StampEntry struct {
+	Stamp    int64  `json:"stamp"`
+	Func     string `json:"func"`
+	Kind     string `json:"kind,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Location string `json:"location"`
+}
+
+// stampedFunc gives the argument positions of the stamp, kind and message of a stamped errx function, -1 when absent.
+type stampedFunc struct {
+	ts   int
+	kind int
+	msg  int
+}
+
+var stampedFuncs = map[string]stampedFunc{
+	"New":              {ts: 0, kind: -1, msg: 1},
+	"Newf":             {ts: 0, kind: -1, msg: 1},
+	"Wrap":             {ts: 0, kind: -1, msg: -1},
+	"Wrapf":            {ts: 0, kind: -1, msg: 1},
+	"NewKind":          {ts: 0, kind: 1, msg: 2},
+	"WrapKind":         {ts: 0, kind: 1, msg: -1},
+	"NewKindf":         {ts: 0, kind: 1, msg: 2},
+	"WrapKindf":        {ts: 0, kind: 1, msg: 2},
+	"Sentinel":         {ts: 0, kind: 1, msg: 2},
+	"NewBuild":         {ts: 0, kind: -1, msg: 1},
+	"BuildFrom":        {ts: 0, kind: -1, msg: -1},
+	"JoinWrap":         {ts: 0, kind: -1, msg: -1},
+	"NewGroup":         {ts: 0, kind: -1, msg: -1},
+	"MustStamp":        {ts: 0, kind: -1, msg: -1},
+	"PanicStamp":       {ts: 0, kind: -1, msg: -1},
+	"Inject":           {ts: 0, kind: -1, msg: -1},
+	"Check":            {ts: 0, kind: -1, msg: -1},
+	"Recover":          {ts: 0, kind: -1, msg: -1},
+	"Safe":             {ts: 0, kind: -1, msg: -1},
+	"GroupWithContext": {ts: 1, kind: -1, msg: -1},
+	"Translate":        {ts: 0, kind: 1, msg: -1},
+}
+
+// kindMethods are the stamped methods of kinds, whose receiver is the kind.
+var kindMethods = map[string]stampedFunc{
+	"New":   {ts: 0, kind: -1, msg: 1},
+	"Newf":  {ts: 0, kind: -1, msg: 1},
+	"Wrap":  {ts: 0, kind: -1, msg: -1},
+	"Wrapf": {ts: 0, kind: -1, msg: 1},
+}
+
+var kindFuncs = map[string]bool{
+	"DefineKind":        true,
+	"DataKind":          true,
+	"SensitiveDataKind": true,
+}
+
+// builtinKinds maps the kinds declared by errx itself onto their kind names.
+var builtinKinds = map[string]string{
+	"RetryAfter":  "retry_after",
+	"Panicked":    "panic",
+	"Canceled":    "canceled",
+	"Timeout":     "timeout",
+	"NotFound":    "notfound",
+	"Permission":  "permission",
+	"Exists":      "exists",
+	"Invalid":     "invalid",
+	"Unavailable": "unavailable",
+}
+
+type scanner struct {
+	root string
+	// module is the module path read from the go.mod file at the root
+	module string
+	fset   *token.FileSet
+	kinds  map[string]*KindEntry
+	// vars maps the package level identifiers kinds are assigned to, qualified by their package import path, onto their kind names
+	vars   map[string]string
+	stamps []StampEntry
+	calls  []pendingCall
+	errs   []string
+}
+
+// fileScope holds what is needed to resolve the identifiers of a scanned file.
+type fileScope struct {
+	// pkg is the import path of the file's package
+	pkg string
+	// alias is the name errx is imported as
+	alias string
+	// imports maps the names of the file's imports onto their import paths
+	imports map[string]string
+	// globals holds the package level variable declarations of the file. Identifiers declared anywhere else in the file are local.
+	globals map[any]bool
+}
+
+// pendingCall is a stamped call whose kind argument is resolved once every file has been scanned.
+type pendingCall struct {
+	entry int
+	kind  ast.Expr
+	scope *fileScope
+	// method reports whether the call is a kind method, which is dropped when its receiver is not a kind
+	method bool
+}
+
+// Scan parses every Go file below root and builds the catalog.
+func Scan(root string, tests bool) (*Catalog, error) {
+	s := &scanner{
+		root:   root,
+		module: modulePath(filepath.Join(root, "go.mod")),
+		fset:   token.NewFileSet(),
+		kinds:  make(map[string]*KindEntry),
+		vars:   make(map[string]string),
+	}
+	for name, kind := range builtinKinds {
+		s.vars[errxPath+"."+name] = kind
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || (!tests && strings.HasSuffix(path, "_test.go")) {
+			return nil
+		}
+		return s.scanFile(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.catalog(), nil
+}
+
+func (s *scanner) scanFile(path string) error {
+	file, err := parser.ParseFile(s.fset, path, nil, 0)
+	if err != nil {
+		// A file that doesn't parse is reported and skipped so the rest of the module is still cataloged
+		s.errs = append(s.errs, err.Error())
+		return nil
+	}
+
+	scope := s.fileScope(path, file)
+	if scope.alias == "" {
+		return nil
+	}
+
+	// Only package level variables can be referred to from other functions, so local assignments are not recorded as kind variables
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			v := spec.(*ast.ValueSpec)
+			scope.globals[v] = true
+			for i, val := range v.Values {
+				if i < len(v.Names) {
+					s.declaration(v.Names[i].Name, val, scope)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			s.call(call, scope)
+		}
+		return true
+	})
+	return nil
+}
+
+// fileScope returns the package and imports of a file.
+func (s *scanner) fileScope(path string, file *ast.File) *fileScope {
+	scope := &fileScope{pkg: s.importPath(filepath.Dir(path)), imports: make(map[string]string), globals: make(map[any]bool)}
+	for _, imp := range file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := importName(p)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		scope.imports[name] = p
+		if p == errxPath {
+			scope.alias = name
+		}
+	}
+	return scope
+}
+
+// importPath returns the import path of the package in dir, relative to the module path when the root has a go.mod file.
+func (s *scanner) importPath(dir string) string {
+	rel, err := filepath.Rel(s.root, dir)
+	if err != nil || rel == "." {
+		return s.module
+	}
+	rel = filepath.ToSlash(rel)
+	if s.module == "" {
+		return rel
+	}
+	return s.module + "/" + rel
+}
+
+// importName returns the default name of an imported package, skipping major version suffixes.
+func importName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+// modulePath returns the module path declared in a go.mod file or an empty string.
+func modulePath(gomod string) string {
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// declaration records kinds declared by assigning a kind constructor to an identifier.
+func (s *scanner) declaration(name string, val ast.Expr, scope *fileScope) {
+	call, ok := val.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	if entry := s.kindCall(call, scope); entry != nil {
+		entry.Var = name
+		s.vars[scope.pkg+"."+name] = entry.Name
+	}
+}
+
+// kindCall records the kind declared by calls to DefineKind, DataKind and SensitiveDataKind.
+func (s *scanner) kindCall(call *ast.CallExpr, scope *fileScope) *KindEntry {
+	fn, typ := errxFunc(call.Fun, scope.alias)
+	if !kindFuncs[fn] || len(call.Args) == 0 {
+		return nil
+	}
+
+	name, ok := stringLit(call.Args[0])
+	if !ok {
+		return nil
+	}
+
+	entry, ok := s.kinds[name]
+	if !ok {
+		entry = &KindEntry{Name: name, Location: s.location(call.Pos())}
+		s.kinds[name] = entry
+	}
+	if typ != nil {
+		entry.DataType = exprString(typ)
+	}
+	if fn == "SensitiveDataKind" {
+		entry.Sensitive = true
+	}
+	if len(call.Args) > 1 {
+		if lit, ok := call.Args[1].(*ast.CompositeLit); ok {
+			readMeta(entry, lit)
+		}
+	}
+	return entry
+}
+
+func (s *scanner) call(call *ast.CallExpr, scope *fileScope) {
+	fn, _ := errxFunc(call.Fun, scope.alias)
+	if fn == "" {
+		s.methodCall(call, scope)
+		return
+	}
+	def, ok := stampedFuncs[fn]
+	if !ok || len(call.Args) <= def.ts {
+		if kindFuncs[fn] {
+			s.kindCall(call, scope)
+		}
+		return
+	}
+
+	stamp, ok := intLit(call.Args[def.ts])
+	if !ok {
+		return
+	}
+
+	entry := StampEntry{Stamp: stamp, Func: fn, Location: s.location(call.Pos())}
+	if def.msg >= 0 && def.msg < len(call.Args) {
+		entry.Message, _ = stringLit(call.Args[def.msg])
+	}
+	s.stamps = append(s.stamps, entry)
+	if def.kind >= 0 && def.kind < len(call.Args) {
+		s.calls = append(s.calls, pendingCall{entry: len(s.stamps) - 1, kind: call.Args[def.kind], scope: scope})
+	}
+}
+
+// methodCall records calls to the stamped methods of kinds, such as NotFound.New(1745397000, "user not found").
+func (s *scanner) methodCall(call *ast.CallExpr, scope *fileScope) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	def, ok := kindMethods[sel.Sel.Name]
+	if !ok || len(call.Args) <= def.ts {
+		return
+	}
+
+	stamp, ok := intLit(call.Args[def.ts])
+	if !ok {
+		return
+	}
+
+	entry := StampEntry{Stamp: stamp, Func: "Kind." + sel.Sel.Name, Location: s.location(call.Pos())}
+	if def.msg >= 0 && def.msg < len(call.Args) {
+		entry.Message, _ = stringLit(call.Args[def.msg])
+	}
+	s.stamps = append(s.stamps, entry)
+	s.calls = append(s.calls, pendingCall{entry: len(s.stamps) - 1, kind: sel.X, scope: scope, method: true})
+}
+
+// resolveKind returns the kind name a kind argument refers to.
+func (s *scanner) resolveKind(expr ast.Expr, scope *fileScope) string {
+	switch v := expr.(type) {
+	case *ast.Ident:
+		if v.Obj != nil && !scope.globals[v.Obj.Decl] {
+			return s.localKind(v, scope)
+		}
+		return s.vars[scope.pkg+"."+v.Name]
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); ok && scope.imports[pkg.Name] != "" {
+			return s.vars[scope.imports[pkg.Name]+"."+v.Sel.Name]
+		}
+		return ""
+	case *ast.ParenExpr:
+		return s.resolveKind(v.X, scope)
+	case *ast.CallExpr:
+		fn, _ := errxFunc(v.Fun, scope.alias)
+		if kindFuncs[fn] {
+			if entry := s.kindCall(v, scope); entry != nil {
+				return entry.Name
+			}
+		}
+		if fn == "Sensitive" && len(v.Args) == 1 {
+			return s.resolveKind(v.Args[0], scope)
+		}
+		// A data kind called with its data
+		return s.resolveKind(v.Fun, scope)
+	}
+	return ""
+}
+
+// localKind returns the kind name a local identifier is assigned, or an empty string when it is not a kind.
+func (s *scanner) localKind(id *ast.Ident, scope *fileScope) string {
+	var names, values []ast.Expr
+	switch d := id.Obj.Decl.(type) {
+	case *ast.AssignStmt:
+		names, values = d.Lhs, d.Rhs
+	case *ast.ValueSpec:
+		for _, name := range d.Names {
+			names = append(names, name)
+		}
+		values = d.Values
+	}
+
+	for i, name := range names {
+		if n, ok := name.(*ast.Ident); ok && n.Name == id.Name && i < len(values) && len(names) == len(values) {
+			return s.resolveKind(values[i], scope)
+		}
+	}
+	return ""
+}
+
+func (s *scanner) catalog() *Catalog {
+	dropped := make(map[int]bool)
+	for _, call := range s.calls {
+		s.stamps[call.entry].Kind = s.resolveKind(call.kind, call.scope)
+		if call.method && s.stamps[call.entry].Kind == "" {
+			dropped[call.entry] = true
+		}
+	}
+	if len(dropped) > 0 {
+		stamps := s.stamps[:0]
+		for i, stamp := range s.stamps {
+			if !dropped[i] {
+				stamps = append(stamps, stamp)
+			}
+		}
+		s.stamps = stamps
+	}
+
+	slices.SortStableFunc(s.stamps, func(a, b StampEntry) int {
+		return cmp.Compare(a.Stamp, b.Stamp)
+	})
+
+	// Stamps are sorted, so a stamp reused by several call sites of a kind is listed once
+	for _, stamp := range s.stamps {
+		if entry, ok := s.kinds[stamp.Kind]; ok && (len(entry.Stamps) == 0 || entry.Stamps[len(entry.Stamps)-1] != stamp.Stamp) {
+			entry.Stamps = append(entry.Stamps, stamp.Stamp)
+		}
+	}
+
+	c := &Catalog{
+		Kinds:  make([]KindEntry, 0, len(s.kinds)),
+		Stamps: s.stamps,
+		Errors: s.errs,
+	}
+	for _, entry := range s.kinds {
+		c.Kinds = append(c.Kinds, *entry)
+	}
+	slices.SortFunc(c.Kinds, func(a, b KindEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return c
+}
+
+func (s *scanner) location(pos token.Pos) string {
+	p := s.fset.Position(pos)
+	rel, err := filepath.Rel(s.root, p.Filename)
+	if err != nil {
+		rel = p.Filename
+	}
+	return filepath.ToSlash(rel) + ":" + strconv.Itoa(p.Line)
+}
+
+// errxFunc returns the name of the errx function called and its type argument if any.
+func errxFunc(fun ast.Expr, alias string) (string, ast.Expr) {
+	var typ ast.Expr
+	if idx, ok := fun.(*ast.IndexExpr); ok {
+		fun, typ = idx.X, idx.Index
+	}
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != alias {
+		return "", nil
+	}
+	return sel.Sel.Name, typ
+}
+
+func readMeta(entry *KindEntry, lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "HTTPStatus":
+			if v, ok := intLit(kv.Value); ok {
+				entry.HTTPStatus = int(v)
+			}
+		case "GRPCCode":
+			if v, ok := intLit(kv.Value); ok {
+				entry.GRPCCode = int(v)
+			}
+		case "Retryable":
+			if id, ok := kv.Value.(*ast.Ident); ok {
+				entry.Retryable = id.Name == "true"
+			}
+		case "Severity":
+			if sel, ok := kv.Value.(*ast.SelectorExpr); ok {
+				entry.Severity = strings.ToLower(strings.TrimPrefix(sel.Sel.Name, "Severity"))
+			}
+		case "Description":
+			entry.Description, _ = stringLit(kv.Value)
+		}
+	}
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	v, err := strconv.Unquote(lit.Value)
+	return v, err == nil
+}
+
+func intLit(expr ast.Expr) (int64, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(lit.Value, 0, 64)
+	return v, err == nil
+}
+
+func exprString(expr ast.Expr) string {
+	switch v := expr.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.SelectorExpr:
+		return exprString(v.X) + "." + v.Sel.Name
+	case *ast.ArrayType:
+		return "[]" + exprString(v.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(v.Key) + "]" + exprString(v.Value)
+	case *ast.StarExpr:
+		return "*" + exprString(v.X)
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	c, err := Scan("testdata/app", false)
+	assert.Nil(t, err)
+
+	assert.Len(t, c.Kinds, 6)
+	email, invalidNo, invoiceMissing, notFound, orderMissing, timeout := c.Kinds[0], c.Kinds[1], c.Kinds[2], c.Kinds[3], c.Kinds[4], c.Kinds[5]
+
+	assert.Equal(t, "email", email.Name)
+	assert.Equal(t, "string", email.DataType)
+	assert.True(t, email.Sensitive)
+
+	assert.Equal(t, "invalidno", invalidNo.Name)
+	assert.Equal(t, "InvalidNo", invalidNo.Var)
+	assert.Equal(t, "int", invalidNo.DataType)
+	assert.Equal(t, []int64{1745397010}, invalidNo.Stamps)
+
+	assert.Equal(t, "notfound", notFound.Name)
+	assert.Equal(t, 404, notFound.HTTPStatus)
+	assert.Equal(t, "info", notFound.Severity)
+	assert.Equal(t, "The resource does not exist", notFound.Description)
+	assert.Equal(t, "kinds.go:8", notFound.Location)
+	assert.Equal(t, []int64{1745397000, 1745397050, 1745397060}, notFound.Stamps)
+
+	assert.Equal(t, "timeout", timeout.Name)
+	assert.Equal(t, 4, timeout.GRPCCode)
+	assert.True(t, timeout.Retryable)
+
+	// Kinds assigned to the same identifier in other packages or in functions don't collide
+	assert.Equal(t, "NotFound", invoiceMissing.Var)
+	assert.Equal(t, "billing/kinds.go:7", invoiceMissing.Location)
+	assert.Equal(t, []int64{1745397100, 1745397110}, invoiceMissing.Stamps)
+	assert.Equal(t, "", orderMissing.Var)
+	assert.Equal(t, []int64{1745397130}, orderMissing.Stamps)
+	assert.Equal(t, []int64{1745397020, 1745397120, 1745397140}, timeout.Stamps)
+
+	assert.Len(t, c.Stamps, 13)
+	assert.Equal(t, StampEntry{Stamp: 1745397000, Func: "Sentinel", Kind: "notfound", Message: "user not found", Location: "kinds.go:13"}, c.Stamps[0])
+	assert.Equal(t, StampEntry{Stamp: 1745397010, Func: "NewKind", Kind: "invalidno", Message: "invalid user id", Location: "users.go:9"}, c.Stamps[1])
+	assert.Equal(t, StampEntry{Stamp: 1745397020, Func: "WrapKind", Kind: "timeout", Location: "users.go:13"}, c.Stamps[2])
+	assert.Equal(t, StampEntry{Stamp: 1745397030, Func: "Wrap", Location: "users.go:15"}, c.Stamps[3])
+	assert.Equal(t, StampEntry{Stamp: 1745397040, Func: "Newf", Message: "lookup of %d failed", Location: "users.go:19"}, c.Stamps[4])
+	assert.Equal(t, StampEntry{Stamp: 1745397050, Func: "Translate", Kind: "notfound", Location: "users.go:23"}, c.Stamps[5])
+	assert.Equal(t, StampEntry{Stamp: 1745397060, Func: "Kind.New", Kind: "notfound", Message: "user missing", Location: "users.go:34"}, c.Stamps[6])
+
+	// Methods of variables that are not kinds are skipped, even when a kind has the same name
+	for _, entry := range c.Stamps {
+		assert.NotContains(t, []int64{1745397070, 1745397080}, entry.Stamp)
+	}
+}
+
+func TestScanSkipsUnparsableFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "kinds.go"), []byte(`package app
+
+import "github.com/michaelolof/errx"
+
+var NotFound = errx.DefineKind("notfound")
+`), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package app\n\nfunc {\n"), 0o644))
+
+	c, err := Scan(dir, false)
+	assert.Nil(t, err)
+	assert.Len(t, c.Kinds, 1)
+	assert.Equal(t, "notfound", c.Kinds[0].Name)
+	assert.Len(t, c.Errors, 1)
+	assert.Contains(t, c.Errors[0], "broken.go")
+}
+
+func TestScanListsReusedStampsOnce(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "kinds.go"), []byte(`package app
+
+import "github.com/michaelolof/errx"
+
+var NotFound = errx.DefineKind("notfound")
+
+func find(err error) error {
+	if err == nil {
+		return errx.NewKind(1, NotFound, "user missing")
+	}
+	return errx.WrapKind(1, NotFound, err)
+}
+`), 0o644))
+
+	c, err := Scan(dir, false)
+	assert.Nil(t, err)
+	assert.Len(t, c.Stamps, 2)
+	assert.Equal(t, []int64{1}, c.Kinds[0].Stamps)
+}
+
+func TestRender(t *testing.T) {
+	c, err := Scan("testdata/app", false)
+	assert.Nil(t, err)
+
+	t.Run("JSON", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.Nil(t, WriteJSON(&b, c))
+
+		var decoded Catalog
+		assert.Nil(t, json.Unmarshal(b.Bytes(), &decoded))
+		assert.Equal(t, c, &decoded)
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.Nil(t, WriteMarkdown(&b, c))
+		assert.Contains(t, b.String(), "| notfound |  | 404 |  |  | info | The resource does not exist | kinds.go:8 | 1745397000, 1745397050, 1745397060 |\n")
+		assert.Contains(t, b.String(), "| 1745397040 | Newf |  | lookup of %d failed | users.go:19 |\n")
+	})
+}
@@ -0,0 +1,58 @@
+// Command errx-catalog scans a module for errx kinds and stamped call sites and prints a searchable catalog.
+//
+// Usage:
+//
+//	errx-catalog [-format markdown|json] [-o file] [-tests] [dir]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "output format: markdown or json")
+	out := flag.String("o", "", "write the catalog to the given file instead of stdout")
+	tests := flag.Bool("tests", false, "include _test.go files")
+	flag.Parse()
+
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	if err := run(root, *format, *out, *tests); err != nil {
+		fmt.Fprintln(os.Stderr, "errx-catalog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(root, format, out string, tests bool) error {
+	catalog, err := Scan(root, tests)
+	if err != nil {
+		return err
+	}
+	for _, msg := range catalog.Errors {
+		fmt.Fprintln(os.Stderr, "errx-catalog: skipped", msg)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "markdown", "md":
+		return WriteMarkdown(w, catalog)
+	case "json":
+		return WriteJSON(w, catalog)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteJSON writes the catalog as indented JSON.
+func WriteJSON(w io.Writer, c *Catalog) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteMarkdown writes the catalog as Markdown tables.
+func WriteMarkdown(w io.Writer, c *Catalog) error {
+	var b strings.Builder
+
+	b.WriteString("# Error catalog\n\n## Kinds\n\n")
+	b.WriteString("| Kind | Data type | HTTP status | gRPC code | Retryable | Severity | Description | Declared at | Stamps |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, k := range c.Kinds {
+		stamps := make([]string, 0, len(k.Stamps))
+		for _, s := range k.Stamps {
+			stamps = append(stamps, strconv.FormatInt(s, 10))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			cell(k.Name), cell(k.DataType), optInt(k.HTTPStatus), optInt(k.GRPCCode), optBool(k.Retryable),
+			cell(k.Severity), cell(k.Description), cell(k.Location), strings.Join(stamps, ", "))
+	}
+
+	b.WriteString("\n## Stamps\n\n")
+	b.WriteString("| Stamp | Func | Kind | Message | Location |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, s := range c.Stamps {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n", s.Stamp, s.Func, cell(s.Kind), cell(s.Message), cell(s.Location))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cell escapes a value so it can't break the table layout.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func optInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func optBool(v bool) string {
+	if v {
+		return "yes"
+	}
+	return ""
+}
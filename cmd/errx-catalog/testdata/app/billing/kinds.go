@@ -0,0 +1,11 @@
+package billing
+
+import (
+	"github.com/michaelolof/errx"
+)
+
+var NotFound = errx.DefineKind("invoice_missing")
+
+func charge() error {
+	return errx.WrapKind(1745397100, NotFound, errx.New(1745397090, "card declined"))
+}
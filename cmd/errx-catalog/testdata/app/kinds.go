@@ -0,0 +1,14 @@
+package app
+
+import (
+	"github.com/michaelolof/errx"
+)
+
+var (
+	NotFound  = errx.DefineKind("notfound", errx.KindMeta{HTTPStatus: 404, Severity: errx.SeverityInfo, Description: "The resource does not exist"})
+	Timeout   = errx.DefineKind("timeout", errx.KindMeta{HTTPStatus: 504, GRPCCode: 4, Retryable: true})
+	InvalidNo = errx.DataKind[int]("invalidno")
+	Email     = errx.SensitiveDataKind[string]("email")
+
+	ErrUserNotFound = errx.Sentinel(1745397000, NotFound, "user not found")
+)
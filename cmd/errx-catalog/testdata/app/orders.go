@@ -0,0 +1,21 @@
+package app
+
+import (
+	"example.com/app/billing"
+	"github.com/michaelolof/errx"
+)
+
+func placeOrder(id int) error {
+	if id < 0 {
+		return errx.WrapKind(1745397110, billing.NotFound, lookup(id))
+	}
+	return errx.WrapKind(1745397120, errx.Timeout, lookup(id))
+}
+
+func cancelOrder(id int) error {
+	NotFound := errx.DefineKind("order_missing")
+	if id < 0 {
+		return errx.NewKind(1745397130, NotFound, "order missing")
+	}
+	return errx.WrapKind(1745397140, Timeout, lookup(id))
+}
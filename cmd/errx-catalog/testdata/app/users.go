@@ -0,0 +1,42 @@
+package app
+
+import (
+	e "github.com/michaelolof/errx"
+)
+
+func findUser(id int) error {
+	if id < 0 {
+		return e.NewKind(1745397010, InvalidNo(id), "invalid user id")
+	}
+	err := lookup(id)
+	if err != nil {
+		return e.WrapKind(1745397020, Timeout, err)
+	}
+	return e.Wrap(1745397030, ErrUserNotFound)
+}
+
+func lookup(id int) error {
+	return e.Newf(1745397040, "lookup of %d failed", id)
+}
+
+var fromStore = e.NewTranslator(
+	e.Translate(1745397050, NotFound).WhenKind(Timeout),
+)
+
+type store struct{}
+
+func (store) Wrap(code int, err error) error {
+	return err
+}
+
+func removeUser(id int) error {
+	if id == 0 {
+		return NotFound.New(1745397060, "user missing")
+	}
+	return store{}.Wrap(1745397070, lookup(id))
+}
+
+func purgeUser(id int) error {
+	Timeout := store{}
+	return Timeout.Wrap(1745397080, lookup(id))
+}
@@ -11,6 +11,9 @@ func Is(err, target error) bool {
 	if err == nil || target == nil {
 		return false
 	}
+	if t, ok := target.(*errx); ok && t.sentinel {
+		return false
+	}
 	return err.Error() == target.Error()
 }
 
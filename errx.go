@@ -3,13 +3,20 @@ package errx
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
 )
 
 // A literal int
 type lint int
-type errKind struct {
-	kind string
-	data dataValue
+
+// Kind is an error kind used for error matching and to carry data. Kinds without data can be used as map keys and compared with ==.
+// Kinds carrying data hold it as an interface value, so data kinds holding slices or maps panic when used as map keys or compared with ==.
+type Kind struct {
+	kind      string
+	data      dataValue
+	sensitive bool
 }
 
 type StampedErr interface {
@@ -20,15 +27,50 @@ type StampedErr interface {
 
 type errx struct {
 	ts   lint
-	kind errKind
+	kind Kind
 	msg  string
 	err  error
 	errx *errx
+	// id is the occurrence assigned to root errors once enabled with UseOccurrenceIDs
+	id *occurrence
+	// at is the creation time in unix nanoseconds once enabled with RecordFrameTimes
+	at int64
+	// sentinel marks errors declared with Sentinel which are matched by stamp identity
+	sentinel bool
+	// parsed marks errors rebuilt from their string, which match sentinels by stamp since the sentinel flag is not rendered
+	parsed bool
+	// rendered caches the output of Error()
+	rendered atomic.Pointer[rendering]
+}
+
+// rendering is a cached error string along with the redaction policy it was rendered with.
+type rendering struct {
+	s      string
+	policy *RedactPolicy
 }
 
-// Implements the error interface by returning the error string
+// cached returns the cached error string if it was rendered with the given redaction policy.
+func (e *errx) cached(policy *RedactPolicy) (string, bool) {
+	if r := e.rendered.Load(); r != nil && r.policy == policy {
+		return r.s, true
+	}
+	return "", false
+}
+
+// Implements the error interface by returning the error string.
+// The string is rendered into a single buffer on the first call and cached for subsequent calls until the redaction policy changes.
 func (e *errx) Error() string {
-	return buildErrx(e).Error()
+	policy := currentRedaction()
+	if s, ok := e.cached(policy); ok {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(e.renderSize())
+	e.renderChain(&b, policy, "")
+	s := b.String()
+	e.rendered.Store(&rendering{s: s, policy: policy})
+	return s
 }
 
 // Returns the string representation of the errx object.
@@ -53,19 +95,15 @@ func (e *errx) Unwrap() error {
 }
 
 // Returns the list of stamp traces for a given error.
+// Joined errors are walked depth first, so the stamps of every branch are included.
 func (e *errx) Stamps() []int {
-	rtn := make([]int, 0, 15)
-	var curr error = e
-	for curr != nil {
-		if v, ok := curr.(interface{ Stamp() int }); ok {
-			stamp := v.Stamp()
-			if stamp != 0 {
-				rtn = append(rtn, stamp)
-			}
-		}
-		curr = Unwrap(curr)
-	}
-	return rtn
+	return Stamps(e)
+}
+
+// Stamps returns the stamp trace of any error chain, including foreign errors wrapping errx errors.
+// Joined errors are walked depth first, so the stamps of every branch are included.
+func Stamps(err error) []int {
+	return frameStamps(Frames(err), make([]int, 0, 15))
 }
 
 // Returns the error interface for the errx instance
@@ -83,10 +121,16 @@ func (e *errx) Kind() string {
 	return e.kind.kind
 }
 
-// Add an error kind to your error object.
-func (e *errx) WithKind(kind errKind) *errx {
-	e.kind = kind
-	return e
+// Returns a copy of the error object with the given error kind. The receiver is left unchanged.
+func (e *errx) WithKind(kind Kind) *errx {
+	c := e.clone()
+	c.kind = kind
+	return c
+}
+
+// clone returns a shallow copy of the frame. Wrapped errors are shared since errx values are never mutated after construction.
+func (e *errx) clone() *errx {
+	return &errx{ts: e.ts, kind: e.kind, msg: e.msg, err: e.err, errx: e.errx, id: e.id, at: e.at, sentinel: e.sentinel, parsed: e.parsed}
 }
 
 // Create a new errx instance and add properties to it using the builder pattern.
@@ -104,9 +148,17 @@ func New(ts lint, msg string) error {
 	return newErr(ts, msg)
 }
 
+// Sentinel returns a package level error given a timestamp, error kind and message.
+// Sentinel errors are matched by their stamp, so errors.Is reports true for any chain containing the sentinel, even after it has been wrapped, formatted with Wrapf or parsed back from its string.
+// Sentinels are shared so they hold no occurrence ID. The error wrapping a sentinel is assigned one instead.
+func Sentinel(ts lint, kind Kind, msg string) error {
+	return &errx{ts: ts, kind: kind, msg: msg, at: frameTime(), sentinel: true}
+}
+
 // Wrap formats an existing error based on the timestamp given and returns the string as a value that satisfies error.
+// Standard library errors are tagged with a built-in kind once enabled with ClassifyStdErrors.
 func Wrap(ts lint, err error) error {
-	return wrapErr(ts, err)
+	return classified(wrapErr(ts, err))
 }
 
 // NewF returns a timestamped error with the message formatted according to a format specifier.
@@ -116,39 +168,45 @@ func Newf(ts lint, pattern string, a ...any) error {
 
 // Wrapf formats an existing error based on the timestamp and formats the existing error message according to the format specifier defined
 func Wrapf(ts lint, pattern string, err error, a ...any) error {
-	return wrapErrf(ts, pattern, err, a...)
+	return classified(wrapErrf(ts, pattern, err, a...))
 }
 
 // NewKind returns a timestamped error with a message and given error kind which can be used to provide context or error matching
-func NewKind(ts lint, kind errKind, msg string) error {
-	return newErr(ts, msg).WithKind(kind)
+func NewKind(ts lint, kind Kind, msg string) error {
+	return kind.New(ts, msg)
 }
 
 // WrapKind wraps an existing error given the timestamp and a given error kind which can be used to provide context or error matching
-func WrapKind(ts lint, kind errKind, err error) error {
-	return wrapErr(ts, err).WithKind(kind)
+func WrapKind(ts lint, kind Kind, err error) error {
+	return kind.Wrap(ts, err)
 }
 
-// NewKind returns a timestamped error with a message and given error kind which can be used to provide context or error matching
-func NewKindf(ts lint, kind errKind, msg string, a ...any) error {
-	return newErrf(ts, msg, a...).WithKind(kind)
+// NewKindf returns a timestamped error with a formatted message and given error kind which can be used to provide context or error matching
+func NewKindf(ts lint, kind Kind, msg string, a ...any) error {
+	return kind.Newf(ts, msg, a...)
 }
 
-// WrapKind wraps an existing error given the timestamp and a given error kind which can be used to provide context or error matching
-func WrapKindf(ts lint, kind errKind, pattern string, err error, a ...any) error {
-	return wrapErrf(ts, pattern, err, a...).WithKind(kind)
+// WrapKindf wraps an existing error given the timestamp, a given error kind and formats the existing error message according to the format specifier defined
+func WrapKindf(ts lint, kind Kind, pattern string, err error, a ...any) error {
+	return kind.Wrapf(ts, pattern, err, a...)
+}
+
+// withKind sets the kind on a freshly constructed error that has not been shared yet.
+func withKind(e *errx, kind Kind) *errx {
+	e.kind = kind
+	return e
 }
 
 func newErr(ts lint, msg string) *errx {
-	return &errx{ts: ts, msg: msg}
+	return &errx{ts: ts, msg: msg, id: rootOccurrence(nil), at: frameTime()}
 }
 
 func wrapErr(ts lint, err error) *errx {
 	switch e := err.(type) {
 	case *errx:
-		return &errx{ts: ts, errx: e}
+		return &errx{ts: ts, errx: e, id: rootOccurrence(e), at: frameTime()}
 	default:
-		return &errx{ts: ts, err: err}
+		return &errx{ts: ts, err: err, id: rootOccurrence(err), at: frameTime()}
 	}
 }
 
@@ -160,10 +218,11 @@ func wrapErrf(ts lint, pattern string, err error, a ...any) *errx {
 	arr := make([]any, 0, len(a)+1)
 	switch v := err.(type) {
 	case *errx:
-		arr = append(arr, v.msg)
+		arr = append(arr, v.chainMsg())
 		arr = append(arr, a...)
-		v.msg = fmt.Sprintf(pattern, arr...)
-		return wrapErr(ts, v)
+		e := wrapErr(ts, v)
+		e.msg = fmt.Sprintf(pattern, arr...)
+		return e
 	default:
 		arr = append(arr, err)
 		arr = append(arr, a...)
@@ -172,72 +231,186 @@ func wrapErrf(ts lint, pattern string, err error, a ...any) *errx {
 	}
 }
 
-func buildErrx(e *errx) error {
-	var details string
+// chainMsg returns the message rendered at the end of the error string: the context of the outermost Wrapf frame or the message of the root.
+func (e *errx) chainMsg() string {
+	for curr := e; curr != nil; curr = curr.errx {
+		if curr.msg != "" {
+			return curr.msg
+		} else if curr.err != nil {
+			return curr.err.Error()
+		}
+	}
+	return ""
+}
+
+// renderChain writes the error string of the chain with the redaction policy applied. The context of a Wrapf frame is rendered in place of the message at the end of the chain, so ctx holds the context of the closest Wrapf frame above.
+func (e *errx) renderChain(b *strings.Builder, policy *RedactPolicy, ctx string) {
+	kind, msg := e.redacted(policy)
+	hasDetails := writeStampDetails(b, e.ts, e.id.String(), kind)
 
-	if e.kind.kind != "" && e.kind.data.isSet {
-		details = fmt.Sprintf("[ts %d kind %s data %s]", e.ts, e.kind.kind, e.kind.data.String())
-	} else if e.kind.kind != "" && !e.kind.data.isSet {
-		details = fmt.Sprintf("[ts %d kind %s]", e.ts, e.kind.kind)
-	} else if e.kind.data.isSet && e.kind.kind == "" {
-		details = fmt.Sprintf("[ts %d data %s]", e.ts, e.kind.data.String())
-	} else if e.ts != 0 {
-		details = fmt.Sprintf("[ts %d]", e.ts)
+	if e.errx != nil || e.err != nil {
+		if ctx == "" {
+			ctx = msg
+		}
+		b.WriteString("; ")
+		if e.err != nil && ctx != "" {
+			b.WriteString(ctx)
+		} else if e.err != nil {
+			b.WriteString(e.err.Error())
+		} else if s, ok := e.errx.cached(policy); ok && ctx == "" {
+			b.WriteString(s)
+		} else {
+			e.errx.renderChain(b, policy, ctx)
+		}
+		return
 	}
 
-	if e.errx != nil {
-		return fmt.Errorf("%s; %s", details, buildErrx(e.errx).Error())
-	} else if e.err != nil {
-		return fmt.Errorf("%s; %s", details, e.err.Error())
-	} else if details != "" {
-		if e.msg != "" {
-			return fmt.Errorf("%s %s", details, e.msg)
+	if ctx != "" {
+		// The Wrapf frame redacted its context with the kinds of every message it embeds, including this one
+		msg = ctx
+	}
+	if msg != "" {
+		if hasDetails {
+			b.WriteByte(' ')
 		}
-		return fmt.Errorf("%s", details)
-	} else {
-		return fmt.Errorf("%s", e.msg)
+		b.WriteString(msg)
 	}
+}
 
+// renderSize estimates the length of the error string so it can be rendered without growing the buffer.
+func (e *errx) renderSize() int {
+	size := 0
+	for curr := e; curr != nil; curr = curr.errx {
+		size += len(curr.msg) + len(curr.id.String()) + len(curr.kind.kind) + len(curr.kind.data.valStr) + 24
+		if r := curr.rendered.Load(); r != nil && curr != e {
+			return size + len(r.s)
+		}
+	}
+	return size
+}
+
+// stampDetails renders the bracketed stamp, occurrence ID, kind and data section of a single frame.
+func stampDetails(ts lint, id string, kind Kind) string {
+	var b strings.Builder
+	writeStampDetails(&b, ts, id, kind)
+	return b.String()
+}
+
+// writeStampDetails writes the bracketed stamp, occurrence ID, kind and data section of a single frame and reports whether anything was written.
+func writeStampDetails(b *strings.Builder, ts lint, id string, kind Kind) bool {
+	if kind.kind == "" && !kind.data.isSet && ts == 0 && id == "" {
+		return false
+	}
+
+	var num [20]byte
+	b.WriteString("[ts ")
+	b.Write(strconv.AppendInt(num[:0], int64(ts), 10))
+	if id != "" {
+		b.WriteString(" id ")
+		b.WriteString(id)
+	}
+	if kind.kind != "" {
+		b.WriteString(" kind ")
+		b.WriteString(kind.kind)
+	}
+	if kind.data.isSet {
+		b.WriteString(" data ")
+		b.WriteString(kind.data.String())
+	}
+	b.WriteByte(']')
+	return true
 }
 
 // LogValue implements slog.LogValuer interface
 func (e *errx) LogValue() slog.Value {
-	attrs := make([]slog.Attr, 0, 4)
+	return logValue(Frames(e))
+}
+
+// LogValue returns the structured log value of any error chain, as (*errx).LogValue does for errx errors.
+// Log adapters for other libraries encode this value so every logger gets the same fields.
+func LogValue(err error) slog.Value {
+	if err == nil {
+		return slog.GroupValue()
+	}
+	return logValue(Frames(err))
+}
 
-	stamps := e.Stamps()
+// logValue returns the log value of the frames with the occurrence ID of the chain at the top level.
+func logValue(frames []Frame) slog.Value {
+	value := framesLogValue(frames)
+	if id := framesOccurrenceID(frames); id != "" {
+		return slog.GroupValue(append([]slog.Attr{slog.String("error_id", id)}, value.Group()...)...)
+	}
+	return value
+}
+
+func framesLogValue(frames []Frame) slog.Value {
+	if len(frames) == 0 {
+		return slog.GroupValue()
+	}
+
+	frame := frames[0]
+	attrs := make([]slog.Attr, 0, 5)
+
+	stamps := frameStamps(frames, make([]int, 0, len(frames)))
 	if len(stamps) > 0 {
 		attrs = append(attrs, slog.Any("error_stamps", stamps))
 	}
 
-	if kind := e.Kind(); kind != "" {
-		attrs = append(attrs, slog.String("error_kind", kind))
+	if frame.Kind != "" {
+		attrs = append(attrs, slog.String("error_kind", frame.Kind))
 	}
 
-	if e.kind.data.isSet {
-		if e.kind.data.val != nil {
-			attrs = append(attrs, slog.Any("error_data", e.kind.data.val))
-		} else if e.kind.data.valStr != "" {
-			attrs = append(attrs, slog.String("error_data_str", e.kind.data.valStr))
+	if frame.kind.data.isSet {
+		if frame.kind.data.val != nil {
+			attrs = append(attrs, slog.Any("error_data", frame.kind.data.val))
+		} else if frame.kind.data.valStr != "" {
+			attrs = append(attrs, slog.String("error_data_str", frame.kind.data.valStr))
 		}
 	}
 
-	if e.msg != "" {
-		attrs = append(attrs, slog.String("error_msg", e.msg))
+	if frame.Msg != "" {
+		attrs = append(attrs, slog.String("error_msg", frame.Msg))
 	}
 
-	if e.errx != nil {
-		attrs = append(attrs, slog.Any("error_cause", e.errx))
-	} else if e.err != nil {
-		attrs = append(attrs, slog.String("error_cause", e.err.Error()))
+	if len(frame.Stack) > 0 {
+		attrs = append(attrs, slog.Any("error_stack", frame.StackTrace()))
+	}
+
+	if !frame.Time.IsZero() {
+		attrs = append(attrs, slog.Time("error_time", frame.Time))
+		if root := rootTime(frames[1:]); !root.IsZero() {
+			attrs = append(attrs, slog.Duration("error_elapsed", frame.Time.Sub(root)))
+		}
+	}
+
+	if len(frame.Branches) > 0 {
+		branches := make([]slog.Attr, 0, len(frame.Branches))
+		for i, branch := range frame.Branches {
+			branches = append(branches, slog.Attr{Key: strconv.Itoa(i), Value: framesLogValue(branch)})
+		}
+		attrs = append(attrs, slog.Attr{Key: "error_branches", Value: slog.GroupValue(branches...)})
+	}
+
+	if len(frames) > 1 {
+		attrs = append(attrs, slog.Attr{Key: "error_cause", Value: framesLogValue(frames[1:])})
 	}
 
 	return slog.GroupValue(attrs...)
 }
 
-// Is implements structural equivalence to avoid string allocation during errors.Is mapping
+// Is implements structural equivalence to avoid string allocation during errors.Is mapping.
+// Sentinel targets are matched by stamp identity: by copies of the sentinel and by errors parsed back from its string.
+// Other errx targets match errors with the same stamp, kind, data and message.
+// Foreign targets are only matched by unstamped errors with the same message, such as plain errors parsed back from a string.
 func (e *errx) Is(target error) bool {
-	if t, ok := target.(*errx); ok {
-		return e.kind.kind == t.kind.kind && e.msg == t.msg && e.kind.data.String() == t.kind.data.String()
+	t, ok := target.(*errx)
+	if !ok {
+		return e.ts == 0 && target != nil && e.Error() == target.Error()
+	} else if t.ts == 0 || e.ts != t.ts {
+		return false
+	} else if t.sentinel {
+		return e.sentinel || e.parsed
 	}
-	return e.Error() == target.Error()
+	return e.kind.kind == t.kind.kind && e.msg == t.msg && e.kind.data.String() == t.kind.data.String()
 }
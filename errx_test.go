@@ -3,6 +3,7 @@ package errx
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,15 +103,17 @@ func TestErrorIs(t *testing.T) {
 		assert.True(t, Is(err, err1))
 	})
 
-	t.Run("Stamps don't have to match. They are not part of error signature", func(t *testing.T) {
+	t.Run("Unrelated errors with the same message and different stamps", func(t *testing.T) {
 		err1 := newErr(100, "same message")
 		err2 := newErr(200, "same message")
-		assert.True(t, Is(err1, err2))
+		assert.False(t, Is(err1, err2))
+		assert.False(t, errors.Is(Wrap(300, err1), err2))
+		assert.True(t, Is(err1, newErr(100, "same message")))
 	})
 
 	t.Run("Mismatching kinds", func(t *testing.T) {
-		err1 := newErr(100, "same message").WithKind(Kind("kind_a"))
-		err2 := newErr(100, "same message").WithKind(Kind("kind_b"))
+		err1 := newErr(100, "same message").WithKind(DefineKind("kind_a"))
+		err2 := newErr(100, "same message").WithKind(DefineKind("kind_b"))
 		assert.False(t, Is(err1, err2))
 	})
 
@@ -343,7 +346,7 @@ func TestWrappedFindDataFromParsedError(t *testing.T) {
 }
 func TestBuilders(t *testing.T) {
 	t.Run("NewBuild", func(t *testing.T) {
-		err := NewBuild(123, "test error").WithKind(Kind("test_kind"))
+		err := NewBuild(123, "test error").WithKind(DefineKind("test_kind"))
 		assert.Equal(t, "[ts 123 kind test_kind] test error", err.Error())
 		assert.Equal(t, 123, err.Stamp())
 		assert.Equal(t, "test_kind", err.Kind())
@@ -351,7 +354,7 @@ func TestBuilders(t *testing.T) {
 
 	t.Run("BuildFrom", func(t *testing.T) {
 		baseErr := errors.New("base error")
-		err := BuildFrom(456, baseErr).WithKind(Kind("wrap_kind"))
+		err := BuildFrom(456, baseErr).WithKind(DefineKind("wrap_kind"))
 		assert.Equal(t, "[ts 456 kind wrap_kind]; base error", err.Error())
 	})
 }
@@ -376,7 +379,7 @@ func TestFormatting(t *testing.T) {
 }
 
 func TestKindFunctions(t *testing.T) {
-	kind := Kind("auth")
+	kind := DefineKind("auth")
 
 	t.Run("NewKind", func(t *testing.T) {
 		err := NewKind(404, kind, "not authorized")
@@ -442,3 +445,192 @@ func TestJoinWrap(t *testing.T) {
 func TestUseLogger(t *testing.T) {
 	UseLogger(nil)
 }
+
+func TestErrorCaching(t *testing.T) {
+	err := newErr(1, "e1")
+	assert.Equal(t, "[ts 1] e1", err.Error())
+
+	withKind := err.WithKind(DefineKind("late"))
+	assert.Equal(t, "[ts 1 kind late] e1", withKind.Error())
+	assert.Equal(t, "[ts 1] e1", err.Error())
+
+	wrapped := wrapErr(2, withKind)
+	assert.Equal(t, "[ts 2]; [ts 1 kind late] e1", wrapped.Error())
+	assert.Equal(t, "[ts 2]; [ts 1 kind late] e1", wrapped.Error())
+}
+
+func TestImmutability(t *testing.T) {
+	t.Run("Wrapf leaves the wrapped error unchanged", func(t *testing.T) {
+		shared := New(1, "shared failure")
+		err := Wrapf(2, "context: %s", shared)
+		assert.Equal(t, "[ts 2]; [ts 1] context: shared failure", err.Error())
+		assert.Equal(t, "[ts 1] shared failure", shared.Error())
+	})
+
+	t.Run("Wrapf adds a frame holding the context", func(t *testing.T) {
+		shared := Wrap(2, New(1, "shared failure"))
+		err := Wrapf(3, "context: %s", shared)
+		err = Wrapf(4, "retry: %s", err)
+		assert.Equal(t, "[ts 4]; [ts 3]; [ts 2]; [ts 1] retry: context: shared failure", err.Error())
+
+		frames := Frames(err)
+		assert.Len(t, frames, 4)
+		assert.Equal(t, "[ts 4] retry: context: shared failure", frames[0].String())
+		assert.Equal(t, "[ts 3] context: shared failure", frames[1].String())
+		assert.Equal(t, "[ts 2]", frames[2].String())
+		assert.Equal(t, "[ts 1] shared failure", frames[3].String())
+		assert.Equal(t, "shared failure", CauseMessage(err))
+		assert.Equal(t, "[ts 2]; [ts 1] shared failure", shared.Error())
+	})
+
+	t.Run("Wrapf over a wrapped foreign error", func(t *testing.T) {
+		err := Wrapf(2, "context: %s", Wrap(1, errors.New("eof")))
+		assert.Equal(t, "[ts 2]; [ts 1]; context: eof", err.Error())
+		assert.Equal(t, "[ts 2] context: eof", Frames(err)[0].String())
+	})
+
+	t.Run("WithKind leaves the receiver unchanged", func(t *testing.T) {
+		shared := newErr(1, "shared failure")
+		err := shared.WithKind(DefineKind("k"))
+		assert.Equal(t, "k", err.Kind())
+		assert.Equal(t, "", shared.Kind())
+	})
+
+	t.Run("Concurrent wrapping of a shared error", func(t *testing.T) {
+		shared := Wrap(2, New(1, "shared failure"))
+
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := Wrapf(lint(100+i), "%s: attempt %d", shared, i)
+				err = WrapKind(3, DefineKind("k"), err)
+				_ = err.Error()
+				_ = err.(*errx).WithKind(DefineKind("other")).Error()
+				_ = Report(err, Indent)
+				_ = shared.Error()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, "[ts 2]; [ts 1] shared failure", shared.Error())
+	})
+}
+
+// legacyBuildErrx is the fmt.Errorf based renderer Error() used before rendering into a single buffer.
+// It is kept to benchmark the current implementation against.
+func legacyBuildErrx(e *errx) error {
+	var details string
+
+	if e.kind.kind != "" && e.kind.data.isSet {
+		details = fmt.Sprintf("[ts %d kind %s data %s]", e.ts, e.kind.kind, e.kind.data.String())
+	} else if e.kind.kind != "" && !e.kind.data.isSet {
+		details = fmt.Sprintf("[ts %d kind %s]", e.ts, e.kind.kind)
+	} else if e.kind.data.isSet && e.kind.kind == "" {
+		details = fmt.Sprintf("[ts %d data %s]", e.ts, e.kind.data.String())
+	} else if e.ts != 0 {
+		details = fmt.Sprintf("[ts %d]", e.ts)
+	}
+
+	if e.errx != nil {
+		return fmt.Errorf("%s; %s", details, legacyBuildErrx(e.errx).Error())
+	} else if e.err != nil {
+		return fmt.Errorf("%s; %s", details, e.err.Error())
+	} else if details != "" {
+		if e.msg != "" {
+			return fmt.Errorf("%s %s", details, e.msg)
+		}
+		return fmt.Errorf("%s", details)
+	} else {
+		return fmt.Errorf("%s", e.msg)
+	}
+}
+
+func deepChain(depth int) *errx {
+	err := newErr(1741599154, "something went wrong").WithKind(DefineKind("root"))
+	for i := 1; i < depth; i++ {
+		err = wrapErr(lint(1741599154+i), err)
+	}
+	return err
+}
+
+func TestLegacyRendering(t *testing.T) {
+	err := deepChain(10)
+	err = wrapErr(1741600000, fmt.Errorf("generic: %w", err)).WithKind(DataKind[int]("count")(10))
+	assert.Equal(t, legacyBuildErrx(err).Error(), err.Error())
+}
+
+func BenchmarkError(b *testing.B) {
+	for _, depth := range []int{1, 10} {
+		b.Run(fmt.Sprintf("legacy/depth=%d", depth), func(b *testing.B) {
+			err := deepChain(depth)
+			b.ReportAllocs()
+			for range b.N {
+				_ = legacyBuildErrx(err).Error()
+			}
+		})
+
+		b.Run(fmt.Sprintf("uncached/depth=%d", depth), func(b *testing.B) {
+			err := deepChain(depth)
+			b.ReportAllocs()
+			for range b.N {
+				err.rendered.Store(nil)
+				_ = err.Error()
+			}
+		})
+
+		b.Run(fmt.Sprintf("cached/depth=%d", depth), func(b *testing.B) {
+			err := deepChain(depth)
+			b.ReportAllocs()
+			for range b.N {
+				_ = err.Error()
+			}
+		})
+	}
+}
+
+func TestSentinel(t *testing.T) {
+	errUserNotFound := Sentinel(1745397000, DefineKind("notfound"), "user not found")
+
+	t.Run("Renders like a kind error", func(t *testing.T) {
+		assert.Equal(t, "[ts 1745397000 kind notfound] user not found", errUserNotFound.Error())
+	})
+
+	t.Run("Matches when wrapped", func(t *testing.T) {
+		err := Wrap(1745397001, errUserNotFound)
+		err = fmt.Errorf("handler: %w", err)
+		err = Wrap(1745397002, err)
+		assert.True(t, errors.Is(err, errUserNotFound))
+		assert.True(t, Is(err, errUserNotFound))
+	})
+
+	t.Run("Matches when formatted with Wrapf", func(t *testing.T) {
+		err := Wrapf(1745397003, "%s: id 42", errUserNotFound)
+		assert.Equal(t, "[ts 1745397003]; [ts 1745397000 kind notfound] user not found: id 42", err.Error())
+		assert.True(t, errors.Is(err, errUserNotFound))
+	})
+
+	t.Run("Matches when parsed", func(t *testing.T) {
+		err := Wrap(1745397004, errUserNotFound)
+		assert.True(t, errors.Is(ParseStampedError(err.Error()), errUserNotFound))
+	})
+
+	t.Run("Unrelated errors with the same message don't match", func(t *testing.T) {
+		err := NewKind(1745397005, DefineKind("notfound"), "user not found")
+		assert.False(t, errors.Is(err, errUserNotFound))
+		assert.False(t, Is(err, errUserNotFound))
+		assert.False(t, Is(errors.New("[ts 1745397000 kind notfound] user not found"), errUserNotFound))
+	})
+
+	t.Run("Errors reusing the stamp of a sentinel don't match", func(t *testing.T) {
+		err := NewKind(1745397000, errUserNotFound.(*errx).kind, "user not found")
+		assert.False(t, errors.Is(Wrap(1745397008, err), errUserNotFound))
+		assert.False(t, errors.Is(errors.New("user not found"), errUserNotFound))
+	})
+
+	t.Run("Distinct sentinels don't match", func(t *testing.T) {
+		errOrderNotFound := Sentinel(1745397006, DefineKind("notfound"), "order not found")
+		assert.False(t, errors.Is(Wrap(1745397007, errOrderNotFound), errUserNotFound))
+	})
+}
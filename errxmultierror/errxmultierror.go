@@ -0,0 +1,21 @@
+// Package errxmultierror adapts github.com/hashicorp/go-multierror errors so errx walks them as joined errors.
+// Stamps, StampPaths, IsKind, Frames and Report then see through every combined error instead of the flattened message.
+package errxmultierror
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/michaelolof/errx"
+)
+
+// Adapter recognizes *multierror.Error and returns its errors as joined errors.
+func Adapter(err error) (errx.Adapted, bool) {
+	if m, ok := err.(*multierror.Error); ok && m != nil {
+		return errx.Adapted{Errors: m.Errors}, true
+	}
+	return errx.Adapted{}, false
+}
+
+// Register registers Adapter with errx. It should be called once at startup.
+func Register() {
+	errx.RegisterAdapter(Adapter)
+}
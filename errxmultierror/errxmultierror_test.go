@@ -0,0 +1,43 @@
+package errxmultierror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	Register()
+}
+
+func TestAdapter(t *testing.T) {
+	userID := errx.DataKind[int]("user_id")
+
+	var merr error
+	merr = multierror.Append(merr, errx.New(1, "e1"))
+	merr = multierror.Append(merr, errx.WrapKind(2, userID(42), errors.New("e2")))
+	err := errx.Wrap(10, merr)
+
+	assert.Equal(t, [][]int{{10, 1}, {10, 2}}, errx.StampPaths(err))
+	assert.True(t, errx.IsKind(err, errx.DefineKind("user_id")))
+
+	data, ok := errx.FindData(err, userID)
+	assert.True(t, ok)
+	assert.Equal(t, 42, *data)
+
+	frames := errx.Frames(err)
+	assert.Len(t, frames, 2)
+	assert.Len(t, frames[1].Branches, 2)
+	assert.Equal(t, "[ts 10];\n  [ts 1] e1;\n  [ts 2 kind user_id data 42];\n    e2", errx.Report(err, errx.Indent))
+}
+
+func TestEmpty(t *testing.T) {
+	assert.Equal(t, [][]int{{1}}, errx.StampPaths(errx.Wrap(1, &multierror.Error{})))
+	_, ok := Adapter(&multierror.Error{})
+	assert.True(t, ok)
+	_, ok = Adapter(errors.New("plain"))
+	assert.False(t, ok)
+}
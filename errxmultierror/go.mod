@@ -0,0 +1,18 @@
+module github.com/michaelolof/errx/errxmultierror
+
+go 1.23.0
+
+require (
+	github.com/hashicorp/go-multierror v1.1.1
+	github.com/michaelolof/errx v0.0.0
+	github.com/stretchr/testify v1.9.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/michaelolof/errx => ../
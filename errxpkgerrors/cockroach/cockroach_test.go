@@ -0,0 +1,52 @@
+// Package cockroach checks that errxpkgerrors covers github.com/cockroachdb/errors.
+// It is its own module so errxpkgerrors does not depend on cockroachdb/errors.
+package cockroach
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/michaelolof/errx"
+	"github.com/michaelolof/errx/errxpkgerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	errxpkgerrors.Register()
+}
+
+func find() error {
+	return errors.New("connection refused")
+}
+
+func TestAdapter(t *testing.T) {
+	err := errx.Wrap(2, errors.Wrap(find(), "find user"))
+
+	frames := errx.Frames(err)
+	assert.Len(t, frames, 3)
+	assert.Equal(t, 2, frames[0].Stamp)
+
+	assert.Equal(t, "find user:", frames[1].Msg)
+	assert.NotEmpty(t, frames[1].Stack)
+	assert.True(t, strings.HasSuffix(frames[1].Stack[0].Function, "cockroach.TestAdapter"), frames[1].Stack[0].Function)
+
+	assert.Equal(t, "connection refused", frames[2].Msg)
+	assert.True(t, strings.HasSuffix(frames[2].Stack[0].Function, "cockroach.find"), frames[2].Stack[0].Function)
+
+	report := errx.Report(err, errx.Indent)
+	assert.Contains(t, report, "find user: (at ")
+	assert.Contains(t, report, "connection refused (at ")
+}
+
+func TestWrappedErrx(t *testing.T) {
+	notFound := errx.DefineKind("notfound")
+	err := errx.Wrap(3, errors.Wrap(errx.NewKind(1, notFound, "user missing"), "find user"))
+
+	assert.Equal(t, [][]int{{3, 1}}, errx.StampPaths(err))
+	assert.True(t, errx.IsKind(err, notFound))
+
+	frames := errx.Frames(err)
+	assert.NotEmpty(t, frames[1].Stack)
+	assert.Equal(t, 1, frames[len(frames)-1].Stamp)
+}
@@ -0,0 +1,31 @@
+module github.com/michaelolof/errx/errxpkgerrors/cockroach
+
+go 1.25.0
+
+require (
+	github.com/cockroachdb/errors v1.14.0
+	github.com/michaelolof/errx v0.0.0
+	github.com/michaelolof/errx/errxpkgerrors v0.0.0
+	github.com/stretchr/testify v1.9.0
+)
+
+require (
+	github.com/cockroachdb/logtags v0.0.0-20230118201751-21c54148d20b // indirect
+	github.com/cockroachdb/redact v1.1.5 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/getsentry/sentry-go v0.46.0 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rogpeppe/go-internal v1.9.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/michaelolof/errx => ../../
+	github.com/michaelolof/errx/errxpkgerrors => ../
+)
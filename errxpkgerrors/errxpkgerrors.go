@@ -0,0 +1,55 @@
+// Package errxpkgerrors adapts github.com/pkg/errors errors so their stacks and causes show up in errx's Frames, Report and LogValue.
+// github.com/cockroachdb/errors records stacks with the same StackTrace interface and is covered as well.
+package errxpkgerrors
+
+import (
+	"runtime"
+
+	"github.com/michaelolof/errx"
+	"github.com/pkg/errors"
+)
+
+// Adapter recognizes errors recording a stack with StackTrace or wrapping a cause with Cause.
+func Adapter(err error) (errx.Adapted, bool) {
+	st, hasStack := err.(interface{ StackTrace() errors.StackTrace })
+	c, hasCause := err.(interface{ Cause() error })
+	if !hasStack && !hasCause {
+		return errx.Adapted{}, false
+	}
+
+	var adapted errx.Adapted
+	if hasStack {
+		adapted.Stack = stack(st.StackTrace())
+	}
+	if hasCause {
+		adapted.Cause = c.Cause()
+	}
+	return adapted, true
+}
+
+// Register registers Adapter with errx. It should be called once at startup.
+func Register() {
+	errx.RegisterAdapter(Adapter)
+}
+
+func stack(st errors.StackTrace) []runtime.Frame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	// A pkg/errors Frame holds the return address recorded by runtime.Callers
+	pcs := make([]uintptr, 0, len(st))
+	for _, f := range st {
+		pcs = append(pcs, uintptr(f))
+	}
+
+	rtn := make([]runtime.Frame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		rtn = append(rtn, frame)
+		if !more {
+			return rtn
+		}
+	}
+}
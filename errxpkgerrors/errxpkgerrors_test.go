@@ -0,0 +1,64 @@
+package errxpkgerrors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	Register()
+}
+
+func find() error {
+	return errors.New("connection refused")
+}
+
+func TestAdapter(t *testing.T) {
+	err := errx.Wrap(2, errors.Wrap(find(), "find user"))
+
+	frames := errx.Frames(err)
+	assert.Len(t, frames, 3)
+	assert.Equal(t, 2, frames[0].Stamp)
+
+	assert.Equal(t, "find user:", frames[1].Msg)
+	assert.NotEmpty(t, frames[1].Stack)
+	assert.True(t, strings.HasSuffix(frames[1].Stack[0].Function, "errxpkgerrors.TestAdapter"), frames[1].Stack[0].Function)
+
+	assert.Equal(t, "connection refused", frames[2].Msg)
+	assert.True(t, strings.HasSuffix(frames[2].Stack[0].Function, "errxpkgerrors.find"), frames[2].Stack[0].Function)
+	assert.Regexp(t, `^connection refused \(at .*errxpkgerrors\.find errxpkgerrors_test\.go:\d+\)$`, frames[2].String())
+	assert.Contains(t, frames[2].StackTrace()[0], "errxpkgerrors_test.go:")
+
+	report := errx.Report(err, errx.Indent)
+	assert.Contains(t, report, "find user: (at ")
+	assert.Contains(t, report, "connection refused (at ")
+}
+
+func TestWithStack(t *testing.T) {
+	err := errors.WithStack(errx.New(1, "user missing"))
+
+	frames := errx.Frames(err)
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "", frames[0].Msg)
+	assert.NotEmpty(t, frames[0].Stack)
+	assert.Equal(t, 1, frames[1].Stamp)
+	assert.Equal(t, []int{1}, errx.StampPaths(err)[0])
+}
+
+// cause implements only Cause, like errors created before pkg/errors supported Unwrap
+type cause struct {
+	err error
+}
+
+func (c cause) Error() string { return "legacy: " + c.err.Error() }
+func (c cause) Cause() error  { return c.err }
+
+func TestCause(t *testing.T) {
+	err := errx.Wrap(2, cause{errx.NewKind(1, errx.DefineKind("notfound"), "user missing")})
+	assert.Equal(t, [][]int{{2, 1}}, errx.StampPaths(err))
+	assert.True(t, errx.IsKind(err, errx.DefineKind("notfound")))
+}
@@ -0,0 +1,17 @@
+module github.com/michaelolof/errx/errxpkgerrors
+
+go 1.23.0
+
+require (
+	github.com/michaelolof/errx v0.0.0
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.9.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/michaelolof/errx => ../
@@ -0,0 +1,299 @@
+// Package errxslog provides a slog.Handler that flattens errx errors into top level attributes log pipelines can index.
+package errxslog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/michaelolof/errx"
+)
+
+// Keys names the attributes errx errors are flattened into. Set a key to "-" to leave the attribute out.
+type Keys struct {
+	// Stamps holds the stamp trace of the error. Defaults to error_stamps
+	Stamps string
+	// ID holds the occurrence ID of the error when occurrence IDs are enabled with errx.UseOccurrenceIDs. Defaults to error_id
+	ID string
+	// Kind holds the kind of the outermost frame with a kind, the one errx.KindInfo and logfmt's err_kind report. Defaults to error_kind
+	Kind string
+	// Data holds a group of the data of every frame keyed by kind. Defaults to error_data
+	Data string
+	// Fingerprint holds a hash of the stamp trace and kinds that groups occurrences of the same failure. Defaults to error_fingerprint
+	Fingerprint string
+}
+
+// Options configures the Handler.
+type Options struct {
+	Keys Keys
+	// Level picks the record level from the error. The record keeps its own level when Level is nil or reports false.
+	// It only applies to records the wrapped handler is enabled for, and the record is dropped if the wrapped handler is not enabled for the picked level.
+	// KindLevel picks the level from the severity declared for the error's kind.
+	Level func(err error) (slog.Level, bool)
+}
+
+// Handler wraps a slog.Handler and flattens the first errx error found in the attributes of a record, including attributes nested in groups.
+// The attribute holding the error is replaced by the error string and the flattened attributes are added at the top level of the record, even within groups opened with WithGroup.
+// Any other errx error, including every error of a record logged through a logger that already flattened one with With, is replaced by a group
+// under its own key holding the error string as msg and its flattened attributes.
+type Handler struct {
+	inner slog.Handler
+	opts  Options
+	// found reports whether an errx error was already flattened by WithAttrs
+	found bool
+	// groups holds the groups opened with WithGroup and the attributes added within them.
+	// They are applied when a record is handled rather than passed to the wrapped handler, so flattened attributes stay at the top level.
+	groups []group
+	// top holds the flattened attributes of an error added with WithAttrs within a group
+	top []slog.Attr
+}
+
+// group is a group opened with WithGroup along with the attributes added within it.
+type group struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewHandler returns a handler writing through inner.
+func NewHandler(inner slog.Handler, opts *Options) *Handler {
+	h := &Handler{inner: inner}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.opts.Keys = h.opts.Keys.withDefaults()
+	return h
+}
+
+func (k Keys) withDefaults() Keys {
+	if k.Stamps == "" {
+		k.Stamps = "error_stamps"
+	}
+	if k.ID == "" {
+		k.ID = "error_id"
+	}
+	if k.Kind == "" {
+		k.Kind = "error_kind"
+	}
+	if k.Data == "" {
+		k.Data = "error_data"
+	}
+	if k.Fingerprint == "" {
+		k.Fingerprint = "error_fingerprint"
+	}
+	return k
+}
+
+// Enabled reports whether the wrapped handler is enabled for the level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	attrs, errAttrs, err := h.flatten(attrs)
+	level := r.Level
+	if err != nil && h.opts.Level != nil {
+		if l, ok := h.opts.Level(err); ok {
+			level = l
+		}
+	}
+	if !h.inner.Enabled(ctx, level) {
+		return nil
+	}
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		attrs = append(slices.Clip(h.groups[i].attrs), attrs...)
+		attrs = []slog.Attr{{Key: h.groups[i].name, Value: slog.GroupValue(attrs...)}}
+	}
+
+	nr := slog.NewRecord(r.Time, level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	nr.AddAttrs(h.top...)
+	nr.AddAttrs(errAttrs...)
+	return h.inner.Handle(ctx, nr)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	attrs, errAttrs, err := h.flatten(slices.Clone(attrs))
+	c.found = h.found || err != nil
+	if len(h.groups) == 0 {
+		c.inner = h.inner.WithAttrs(append(attrs, errAttrs...))
+		return &c
+	}
+
+	c.groups = slices.Clone(h.groups)
+	last := &c.groups[len(c.groups)-1]
+	last.attrs = append(slices.Clip(last.attrs), attrs...)
+	c.top = append(slices.Clip(h.top), errAttrs...)
+	return &c
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.groups = append(slices.Clip(h.groups), group{name: name})
+	return &c
+}
+
+// flatten replaces the errx errors in the attributes, including nested groups, and returns the flattened attributes of the first one
+// when no error was flattened by WithAttrs before.
+func (h *Handler) flatten(attrs []slog.Attr) ([]slog.Attr, []slog.Attr, error) {
+	var first error
+	for i, a := range attrs {
+		if a, ok := h.replaceErrs(a, &first); ok {
+			attrs[i] = a
+		}
+	}
+	if first == nil {
+		return attrs, nil, nil
+	}
+	return attrs, h.errAttrs(first), first
+}
+
+// replaceErrs returns the attribute with the errx errors in it replaced and reports whether it held any.
+// The first error flattened at the top level is replaced by its string and stored in first, any other by a group of its own.
+func (h *Handler) replaceErrs(a slog.Attr, first *error) (slog.Attr, bool) {
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		var replaced []slog.Attr
+		for i, ga := range group {
+			if ga, ok := h.replaceErrs(ga, first); ok {
+				if replaced == nil {
+					replaced = slices.Clone(group)
+				}
+				replaced[i] = ga
+			}
+		}
+		if replaced == nil {
+			return a, false
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(replaced...)}, true
+	}
+
+	err, ok := a.Value.Any().(error)
+	if !ok || !isErrx(err) {
+		return a, false
+	} else if !h.found && *first == nil {
+		*first = err
+		return slog.String(a.Key, err.Error()), true
+	}
+	attrs := append([]slog.Attr{slog.String(slog.MessageKey, err.Error())}, h.errAttrs(err)...)
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}, true
+}
+
+func (h *Handler) errAttrs(err error) []slog.Attr {
+	frames := errx.Frames(err)
+	rtn := make([]slog.Attr, 0, 4)
+
+	stamps := errx.Stamps(err)
+	if h.opts.Keys.Stamps != "-" && len(stamps) > 0 {
+		rtn = append(rtn, slog.Any(h.opts.Keys.Stamps, stamps))
+	}
+
+	if id := errx.OccurrenceID(err); h.opts.Keys.ID != "-" && id != "" {
+		rtn = append(rtn, slog.String(h.opts.Keys.ID, id))
+	}
+
+	kind := outerKind(frames)
+	if h.opts.Keys.Kind != "-" && kind != "" {
+		rtn = append(rtn, slog.String(h.opts.Keys.Kind, kind))
+	}
+
+	if h.opts.Keys.Data != "-" {
+		if data := dataAttrs(frames, nil); len(data) > 0 {
+			rtn = append(rtn, slog.Attr{Key: h.opts.Keys.Data, Value: slog.GroupValue(data...)})
+		}
+	}
+
+	if h.opts.Keys.Fingerprint != "-" {
+		rtn = append(rtn, slog.String(h.opts.Keys.Fingerprint, Fingerprint(err)))
+	}
+	return rtn
+}
+
+// KindLevel picks the record level from the severity declared for the error's kind with errx.KindMeta.
+func KindLevel(err error) (slog.Level, bool) {
+	meta, ok := errx.KindInfo(err)
+	if !ok {
+		return 0, false
+	}
+
+	switch meta.Severity {
+	case errx.SeverityDebug:
+		return slog.LevelDebug, true
+	case errx.SeverityInfo:
+		return slog.LevelInfo, true
+	case errx.SeverityWarning:
+		return slog.LevelWarn, true
+	case errx.SeverityError:
+		return slog.LevelError, true
+	case errx.SeverityCritical:
+		return slog.LevelError + 4, true
+	}
+	return 0, false
+}
+
+// Fingerprint returns a short hash of the stamp trace and kinds of the error.
+// Occurrences of the same failure share a fingerprint regardless of their messages and data.
+func Fingerprint(err error) string {
+	h := sha256.New()
+	writeFingerprint(h, errx.Frames(err))
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+func writeFingerprint(h interface{ Write([]byte) (int, error) }, frames []errx.Frame) {
+	for _, frame := range frames {
+		h.Write([]byte(strconv.Itoa(frame.Stamp)))
+		h.Write([]byte{':'})
+		h.Write([]byte(frame.Kind))
+		h.Write([]byte{';'})
+		for _, branch := range frame.Branches {
+			h.Write([]byte{'('})
+			writeFingerprint(h, branch)
+			h.Write([]byte{')'})
+		}
+	}
+}
+
+func isErrx(err error) bool {
+	var s interface{ Stamps() []int }
+	return errors.As(err, &s)
+}
+
+func outerKind(frames []errx.Frame) string {
+	for _, frame := range frames {
+		if frame.Kind != "" {
+			return frame.Kind
+		}
+		for _, branch := range frame.Branches {
+			if kind := outerKind(branch); kind != "" {
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+func dataAttrs(frames []errx.Frame, rtn []slog.Attr) []slog.Attr {
+	for _, frame := range frames {
+		if frame.Data != nil && frame.Kind != "" {
+			rtn = append(rtn, slog.Any(frame.Kind, frame.Data))
+		}
+		for _, branch := range frame.Branches {
+			rtn = dataAttrs(branch, rtn)
+		}
+	}
+	return rtn
+}
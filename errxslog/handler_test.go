@@ -0,0 +1,171 @@
+package errxslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	notFound = errx.DefineKind("errxslog_notfound", errx.KindMeta{Severity: errx.SeverityWarning})
+	userID   = errx.DataKind[int]("user_id")
+	critical = errx.DefineKind("errxslog_critical", errx.KindMeta{Severity: errx.SeverityCritical})
+)
+
+func newLogger(opts *Options) (*slog.Logger, *bytes.Buffer) {
+	var b bytes.Buffer
+	inner := slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelInfo})
+	return slog.New(NewHandler(inner, opts)), &b
+}
+
+func decode(t *testing.T, b *bytes.Buffer) map[string]any {
+	var rtn map[string]any
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &rtn))
+	return rtn
+}
+
+func sample() error {
+	err := errx.NewKind(1, notFound, "user missing")
+	err = errx.WrapKind(2, userID(42), err)
+	err = fmt.Errorf("handler: %w", err)
+	return errx.Wrap(3, err)
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("Flattens errx attributes", func(t *testing.T) {
+		logger, b := newLogger(nil)
+		logger.Info("request failed", "err", sample())
+
+		rec := decode(t, b)
+		assert.Equal(t, sample().Error(), rec["err"])
+		assert.Equal(t, []any{3.0, 2.0, 1.0}, rec["error_stamps"])
+		assert.Equal(t, "user_id", rec["error_kind"])
+		assert.Equal(t, map[string]any{"user_id": 42.0}, rec["error_data"])
+		assert.Equal(t, Fingerprint(sample()), rec["error_fingerprint"])
+	})
+
+	t.Run("Custom keys", func(t *testing.T) {
+		logger, b := newLogger(&Options{Keys: Keys{Stamps: "stamps", Data: "-", Fingerprint: "-"}})
+		logger.Info("request failed", "err", sample())
+
+		rec := decode(t, b)
+		assert.Equal(t, []any{3.0, 2.0, 1.0}, rec["stamps"])
+		assert.Equal(t, "user_id", rec["error_kind"])
+		assert.NotContains(t, rec, "error_data")
+		assert.NotContains(t, rec, "error_fingerprint")
+	})
+
+	t.Run("Occurrence ID", func(t *testing.T) {
+		errx.UseOccurrenceIDs(true)
+		defer errx.UseOccurrenceIDs(false)
+
+		err := sample()
+		logger, b := newLogger(nil)
+		logger.Info("request failed", "err", err)
+
+		rec := decode(t, b)
+		assert.Equal(t, errx.OccurrenceID(err), rec["error_id"])
+		assert.Len(t, rec["error_id"], 26)
+	})
+
+	t.Run("Nested groups and WithAttrs", func(t *testing.T) {
+		logger, b := newLogger(nil)
+		logger.With("req", slog.GroupValue(slog.String("id", "r1"), slog.Any("err", sample()))).Info("request failed")
+
+		rec := decode(t, b)
+		assert.Equal(t, map[string]any{"id": "r1", "err": sample().Error()}, rec["req"])
+		assert.Equal(t, "user_id", rec["error_kind"])
+	})
+
+	t.Run("WithGroup", func(t *testing.T) {
+		logger, b := newLogger(nil)
+		logger.WithGroup("req").With("id", "r1").WithGroup("db").Info("request failed", "err", sample(), "table", "users")
+
+		rec := decode(t, b)
+		assert.Equal(t, map[string]any{"id": "r1", "db": map[string]any{"err": sample().Error(), "table": "users"}}, rec["req"])
+		assert.Equal(t, "user_id", rec["error_kind"])
+		assert.Equal(t, []any{3.0, 2.0, 1.0}, rec["error_stamps"])
+	})
+
+	t.Run("WithGroup and WithAttrs", func(t *testing.T) {
+		logger, b := newLogger(nil)
+		logger.WithGroup("req").With("err", sample()).Info("request failed", "id", "r1")
+
+		rec := decode(t, b)
+		assert.Equal(t, map[string]any{"id": "r1", "err": sample().Error()}, rec["req"])
+		assert.Equal(t, "user_id", rec["error_kind"])
+
+		b.Reset()
+		logger.WithGroup("empty").Info("request failed")
+		assert.NotContains(t, decode(t, b), "empty")
+	})
+
+	t.Run("Every error is flattened", func(t *testing.T) {
+		logger, b := newLogger(nil)
+		e1, e2 := sample(), errx.NewKind(5, notFound, "order missing")
+		logger.With("err", e1).Info("request failed", "err2", e2)
+
+		rec := decode(t, b)
+		assert.Equal(t, e1.Error(), rec["err"])
+		assert.Equal(t, []any{3.0, 2.0, 1.0}, rec["error_stamps"])
+		assert.Equal(t, map[string]any{
+			"msg":               e2.Error(),
+			"error_stamps":      []any{5.0},
+			"error_kind":        "errxslog_notfound",
+			"error_fingerprint": Fingerprint(e2),
+		}, rec["err2"])
+
+		logger, b = newLogger(nil)
+		logger.Info("request failed", "err", e1, "err2", e2)
+		rec = decode(t, b)
+		assert.Equal(t, e1.Error(), rec["err"])
+		assert.Equal(t, "user_id", rec["error_kind"])
+		assert.Equal(t, e2.Error(), rec["err2"].(map[string]any)["msg"])
+	})
+
+	t.Run("Foreign errors are untouched", func(t *testing.T) {
+		logger, b := newLogger(nil)
+		logger.Info("request failed", "err", fmt.Errorf("plain"))
+
+		rec := decode(t, b)
+		assert.NotContains(t, rec, "error_stamps")
+	})
+
+	t.Run("Level from kind", func(t *testing.T) {
+		logger, b := newLogger(&Options{Level: KindLevel})
+
+		logger.Info("request failed", "err", errx.NewKind(1, critical, "disk full"))
+		rec := decode(t, b)
+		assert.Equal(t, "ERROR+4", rec["level"])
+
+		// Records below the wrapped handler's level are dropped before their errors are read
+		b.Reset()
+		logger.Debug("request failed", "err", errx.NewKind(1, critical, "disk full"))
+		assert.Equal(t, 0, b.Len())
+		assert.False(t, logger.Handler().Enabled(context.Background(), slog.LevelDebug))
+
+		b.Reset()
+		logger.Info("request failed", "err", sample())
+		assert.Equal(t, "WARN", decode(t, b)["level"])
+
+		b.Reset()
+		logger.Debug("request failed", "err", errx.New(1, "no kind"))
+		assert.Equal(t, 0, b.Len())
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	a := errx.Wrap(2, errx.NewKind(1, userID(1), "first"))
+	b := errx.Wrap(2, errx.NewKind(1, userID(2), "second"))
+	c := errx.Wrap(3, errx.NewKind(1, userID(1), "first"))
+
+	assert.Equal(t, Fingerprint(a), Fingerprint(b))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(c))
+	assert.Len(t, Fingerprint(a), 16)
+}
@@ -0,0 +1,23 @@
+// Package errxsql classifies database/sql errors with errx's built-in kinds, so errx itself does not link database/sql.
+package errxsql
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/michaelolof/errx"
+)
+
+// Classifier tags sql.ErrNoRows with errx.NotFound.
+func Classifier(err error) (errx.Kind, bool) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errx.NotFound, true
+	}
+	return errx.Kind{}, false
+}
+
+// Register registers Classifier with errx. It should be called once at startup.
+// Errors are only classified once enabled with errx.ClassifyStdErrors.
+func Register() {
+	errx.RegisterClassifier(Classifier)
+}
@@ -0,0 +1,28 @@
+package errxsql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	Register()
+}
+
+func TestClassifier(t *testing.T) {
+	errx.ClassifyStdErrors(true)
+	defer errx.ClassifyStdErrors(false)
+
+	err := errx.Wrap(1, fmt.Errorf("scan user: %w", sql.ErrNoRows))
+	assert.True(t, errx.IsKind(err, errx.NotFound))
+
+	meta, ok := errx.KindInfo(err)
+	assert.True(t, ok)
+	assert.Equal(t, 404, meta.HTTPStatus)
+
+	assert.False(t, errx.IsKind(errx.Wrap(1, sql.ErrTxDone), errx.NotFound))
+}
@@ -0,0 +1,143 @@
+// Package errxtest provides assertions for testing errx errors structurally instead of comparing whole error strings.
+// Every assertion works with both live errors and errors parsed back with errx.ParseStampedError.
+package errxtest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/michaelolof/errx"
+)
+
+// AssertStamps asserts that the stamp trace of err, walked depth first through joined errors, equals the given stamps.
+func AssertStamps(t testing.TB, err error, stamps ...int) bool {
+	t.Helper()
+	got := Stamps(err)
+	if !reflect.DeepEqual(got, stamps) && !(len(got) == 0 && len(stamps) == 0) {
+		t.Errorf("stamps mismatch\n  got:  %v\n  want: %v\n  error: %v", got, stamps, err)
+		return false
+	}
+	return true
+}
+
+// AssertKind asserts that a frame of err has the given kind.
+func AssertKind(t testing.TB, err error, kind errx.Kind) bool {
+	t.Helper()
+	if _, ok := findKind(errx.Frames(err), kind.Name()); !ok {
+		t.Errorf("kind %q not found\n  error: %v", kind.Name(), err)
+		return false
+	}
+	return true
+}
+
+// AssertData asserts that the first frame of err with the given data kind holds the wanted data.
+func AssertData[T errx.DataType](t testing.TB, err error, kind func(T) errx.Kind, want T) bool {
+	t.Helper()
+	var zero T
+	name := kind(zero).Name()
+
+	frame, ok := findKind(errx.Frames(err), name)
+	if !ok {
+		t.Errorf("kind %q not found\n  error: %v", name, err)
+		return false
+	}
+
+	got, ok := errx.FrameData[T](frame)
+	if !ok {
+		t.Errorf("kind %q holds %s which is not a %T\n  error: %v", name, frame.DataString(), zero, err)
+		return false
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("kind %q data mismatch\n  got:  %#v\n  want: %#v\n  error: %v", name, *got, want, err)
+		return false
+	}
+	return true
+}
+
+// AssertRootCause asserts that the root cause of err is target, either by errors.Is or by having the same frames.
+func AssertRootCause(t testing.TB, err error, target error) bool {
+	t.Helper()
+	root := errx.Cause(err)
+	if errors.Is(root, target) {
+		return true
+	}
+	if diff := Diff(root, target); diff != "" {
+		t.Errorf("root cause mismatch\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// AssertEqual asserts that got and want have the same frames, printing the frames that differ.
+func AssertEqual(t testing.TB, got, want error) bool {
+	t.Helper()
+	if diff := Diff(got, want); diff != "" {
+		t.Errorf("errors differ\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// Diff compares the frames of two errors and describes every frame that differs, or returns an empty string when they match.
+// Frames are compared by stamp, kind, data and message, so a live error and the same error parsed back are equal.
+func Diff(got, want error) string {
+	var b strings.Builder
+	diffFrames(&b, "", errx.Frames(got), errx.Frames(want))
+	return b.String()
+}
+
+func diffFrames(b *strings.Builder, path string, got, want []errx.Frame) {
+	for i := 0; i < max(len(got), len(want)); i++ {
+		at := fmt.Sprintf("%sframe %d", path, i)
+		if i >= len(got) {
+			fmt.Fprintf(b, "%s: missing, want %q\n", at, want[i].String())
+			continue
+		} else if i >= len(want) {
+			fmt.Fprintf(b, "%s: unexpected %q\n", at, got[i].String())
+			continue
+		}
+
+		g, w := got[i], want[i]
+		if g.Stamp != w.Stamp {
+			fmt.Fprintf(b, "%s: stamp %d, want %d\n", at, g.Stamp, w.Stamp)
+		}
+		if g.Kind != w.Kind {
+			fmt.Fprintf(b, "%s: kind %q, want %q\n", at, g.Kind, w.Kind)
+		}
+		if g.DataString() != w.DataString() {
+			fmt.Fprintf(b, "%s: data %s, want %s\n", at, g.DataString(), w.DataString())
+		}
+		if g.Msg != w.Msg {
+			fmt.Fprintf(b, "%s: msg %q, want %q\n", at, g.Msg, w.Msg)
+		}
+
+		if len(g.Branches) != len(w.Branches) {
+			fmt.Fprintf(b, "%s: %d joined errors, want %d\n", at, len(g.Branches), len(w.Branches))
+		}
+		for j := 0; j < min(len(g.Branches), len(w.Branches)); j++ {
+			diffFrames(b, fmt.Sprintf("%s branch %d ", at, j), g.Branches[j], w.Branches[j])
+		}
+	}
+}
+
+// Stamps returns the stamp trace of err, walking joined errors depth first.
+func Stamps(err error) []int {
+	return errx.Stamps(err)
+}
+
+func findKind(frames []errx.Frame, name string) (errx.Frame, bool) {
+	for _, frame := range frames {
+		if frame.Kind == name {
+			return frame, true
+		}
+		for _, branch := range frame.Branches {
+			if f, ok := findKind(branch, name); ok {
+				return f, true
+			}
+		}
+	}
+	return errx.Frame{}, false
+}
@@ -0,0 +1,101 @@
+package errxtest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	notFound = errx.DefineKind("notfound")
+	userID   = errx.DataKind[int]("user_id")
+	tags     = errx.DataKind[[]string]("tags")
+)
+
+// recorder captures assertion failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func sample() error {
+	err := errx.NewKind(1, userID(42), "user missing")
+	err = errx.WrapKind(2, notFound, err)
+	err = fmt.Errorf("handler: %w", err)
+	return errx.WrapKind(3, tags([]string{"a", "b"}), err)
+}
+
+func TestAssertions(t *testing.T) {
+	for name, err := range map[string]error{
+		"live":   sample(),
+		"parsed": errx.ParseStampedError(sample().Error()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			assert.True(t, AssertStamps(r, err, 3, 2, 1))
+			assert.True(t, AssertKind(r, err, notFound))
+			assert.True(t, AssertData(r, err, userID, 42))
+			assert.True(t, AssertData(r, err, tags, []string{"a", "b"}))
+			assert.True(t, AssertRootCause(r, err, errx.NewKind(1, userID(42), "user missing")))
+			assert.Empty(t, r.failures)
+		})
+	}
+}
+
+func TestAssertionFailures(t *testing.T) {
+	err := sample()
+
+	r := &recorder{TB: t}
+	assert.False(t, AssertStamps(r, err, 3, 1))
+	assert.False(t, AssertKind(r, err, errx.DefineKind("other")))
+	assert.False(t, AssertData(r, err, userID, 7))
+	assert.False(t, AssertData(r, err, errx.DataKind[int]("missing"), 7))
+	assert.False(t, AssertRootCause(r, err, errors.New("other")))
+
+	assert.Len(t, r.failures, 5)
+	assert.Contains(t, r.failures[0], "got:  [3 2 1]")
+	assert.Contains(t, r.failures[2], "got:  42")
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("Equal", func(t *testing.T) {
+		assert.Equal(t, "", Diff(sample(), errx.ParseStampedError(sample().Error())))
+	})
+
+	t.Run("Differing frames", func(t *testing.T) {
+		want := errx.WrapKind(2, notFound, errx.NewKind(1, userID(43), "user missing"))
+		got := errx.Wrap(5, errx.NewKind(1, userID(42), "user gone"))
+
+		assert.Equal(t, `frame 0: stamp 5, want 2
+frame 0: kind "", want "notfound"
+frame 1: data 42, want 43
+frame 1: msg "user gone", want "user missing"
+`, Diff(got, want))
+	})
+
+	t.Run("Missing frames", func(t *testing.T) {
+		assert.Equal(t, "frame 1: missing, want \"[ts 1] e1\"\n", Diff(errx.Wrap(2, nil), errx.Wrap(2, errx.New(1, "e1"))))
+	})
+
+	t.Run("Joined errors", func(t *testing.T) {
+		got := errx.JoinWrap(10, errx.New(1, "e1"), errx.New(2, "e2"))
+		want := errx.JoinWrap(10, errx.New(1, "e1"), errx.New(3, "e2"))
+		assert.Equal(t, "frame 1 branch 1 frame 0: stamp 2, want 3\n", Diff(got, want))
+	})
+
+	t.Run("AssertEqual", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.True(t, AssertEqual(r, sample(), sample()))
+		assert.False(t, AssertEqual(r, sample(), errx.New(1, "e1")))
+		assert.Len(t, r.failures, 1)
+	})
+}
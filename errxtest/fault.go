@@ -0,0 +1,20 @@
+package errxtest
+
+import (
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/michaelolof/errx/internal/fault"
+)
+
+// FailAt makes errx.Inject and errx.Check at the given stamp return an error of the given kind until the test ends.
+func FailAt(t testing.TB, ts int, kind errx.Kind) {
+	t.Helper()
+	t.Cleanup(fault.Set(ts, kind))
+}
+
+// FailWith makes errx.Inject and errx.Check at the given stamp return err wrapped with the stamp until the test ends.
+func FailWith(t testing.TB, ts int, err error) {
+	t.Helper()
+	t.Cleanup(fault.Set(ts, err))
+}
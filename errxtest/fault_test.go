@@ -0,0 +1,98 @@
+package errxtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/michaelolof/errx/internal/fault"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadUser(id int) error {
+	if err := errx.Inject(1745397000); err != nil {
+		return errx.Wrap(1745397001, err)
+	}
+	return nil
+}
+
+func pingDB(ping error) error {
+	return errx.Check(1745397010, ping)
+}
+
+func TestFailAt(t *testing.T) {
+	assert.Nil(t, loadUser(1))
+
+	t.Run("Kind", func(t *testing.T) {
+		FailAt(t, 1745397000, notFound)
+
+		err := loadUser(1)
+		AssertStamps(t, err, 1745397001, 1745397000)
+		AssertKind(t, err, notFound)
+		assert.Equal(t, "[ts 1745397001]; [ts 1745397000 kind notfound] injected fault", err.Error())
+	})
+
+	t.Run("Data kind", func(t *testing.T) {
+		FailAt(t, 1745397000, userID(7))
+		AssertData(t, loadUser(1), userID, 7)
+	})
+
+	t.Run("Removed after the test", func(t *testing.T) {
+		assert.Nil(t, loadUser(1))
+	})
+
+	t.Run("Uncomparable data kind", func(t *testing.T) {
+		roles := errx.DataKind[[]string]("roles")
+		t.Run("Registered", func(t *testing.T) {
+			FailAt(t, 1745397000, roles([]string{"admin"}))
+			AssertData(t, loadUser(1), roles, []string{"admin"})
+		})
+		assert.Nil(t, loadUser(1))
+	})
+
+	t.Run("Same fault registered twice", func(t *testing.T) {
+		removeFirst := fault.Set(1745397000, notFound)
+		removeSecond := fault.Set(1745397000, notFound)
+
+		removeFirst()
+		AssertKind(t, loadUser(1), notFound)
+		removeSecond()
+		assert.Nil(t, loadUser(1))
+	})
+
+	t.Run("Nested override", func(t *testing.T) {
+		FailAt(t, 1745397000, notFound)
+		t.Run("Override", func(t *testing.T) {
+			FailWith(t, 1745397000, errors.New("connection refused"))
+			assert.Equal(t, "[ts 1745397001]; [ts 1745397000]; connection refused", loadUser(1).Error())
+		})
+		AssertKind(t, loadUser(1), notFound)
+	})
+
+	t.Run("Removed out of order", func(t *testing.T) {
+		removeFirst := fault.Set(1745397000, notFound)
+		removeSecond := fault.Set(1745397000, userID(7))
+
+		removeFirst()
+		AssertData(t, loadUser(1), userID, 7)
+		removeSecond()
+		assert.Nil(t, loadUser(1))
+	})
+}
+
+func TestFailWith(t *testing.T) {
+	base := errors.New("connection refused")
+	assert.Nil(t, pingDB(nil))
+
+	t.Run("Injected", func(t *testing.T) {
+		FailWith(t, 1745397010, base)
+		err := pingDB(nil)
+		assert.True(t, errors.Is(err, base))
+		AssertStamps(t, err, 1745397010)
+	})
+
+	t.Run("Real errors are wrapped", func(t *testing.T) {
+		err := pingDB(base)
+		assert.Equal(t, "[ts 1745397010]; connection refused", err.Error())
+	})
+}
@@ -0,0 +1,81 @@
+package errxtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/michaelolof/errx"
+)
+
+// updateEnv is the environment variable that makes golden comparisons rewrite the golden files when set to 1 or true.
+const updateEnv = "ERRXTEST_UPDATE"
+
+// updating reports whether golden files should be rewritten: when ERRXTEST_UPDATE is set or when the test package defines its own -update flag and it is set.
+// errxtest doesn't register a flag itself so it can't clash with the -update flag of the package under test.
+func updating() bool {
+	if v, err := strconv.ParseBool(os.Getenv(updateEnv)); err == nil {
+		return v
+	}
+
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	if g, ok := f.Value.(flag.Getter); ok {
+		v, _ := g.Get().(bool)
+		return v
+	}
+	return f.Value.String() == "true"
+}
+
+// Golden compares err.Error() against the golden file testdata/<name>.golden.
+// Running the tests with ERRXTEST_UPDATE=1 rewrites the golden file instead.
+func Golden(t testing.TB, name string, err error) bool {
+	t.Helper()
+	if err == nil {
+		return golden(t, name, "<nil>")
+	}
+	return golden(t, name, err.Error())
+}
+
+// GoldenReport compares the error rendered by errx.Report with the given mode against the golden file testdata/<name>.golden.
+// Running the tests with ERRXTEST_UPDATE=1 rewrites the golden file instead.
+func GoldenReport(t testing.TB, name string, err error, mode errx.ReportMode) bool {
+	t.Helper()
+	if err == nil {
+		return golden(t, name, "<nil>")
+	}
+	return golden(t, name, errx.Report(err, mode))
+}
+
+func golden(t testing.TB, name, got string) bool {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got += "\n"
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("creating golden directory: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Errorf("writing golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file %s: %v (run with ERRXTEST_UPDATE=1 to create it)", path, err)
+		return false
+	}
+	if string(want) != got {
+		t.Errorf("%s mismatch (run with ERRXTEST_UPDATE=1 to rewrite it)\n  got:\n%s\n  want:\n%s", path, got, want)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package errxtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+// update is declared like the -update flag of golden file tests in packages using errxtest, which must not clash with errxtest.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestGolden(t *testing.T) {
+	err := errx.JoinWrap(10,
+		errx.Wrap(11, errx.NewKind(1, userID(42), "user missing")),
+		errx.Wrap(12, errx.New(2, "db down")),
+	)
+
+	Golden(t, "joined", err)
+	GoldenReport(t, "joined_indent", err, errx.Indent)
+	GoldenReport(t, "joined_reversed", err, errx.Reversed)
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	r := &recorder{TB: t}
+	assert.False(t, Golden(r, "joined", errx.New(1, "something else")))
+	assert.False(t, Golden(r, "does_not_exist", errx.New(1, "something else")))
+	assert.Len(t, r.failures, 2)
+	assert.Contains(t, r.failures[0], "testdata/joined.golden mismatch")
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	name := "update_check"
+	path := filepath.Join("testdata", name+".golden")
+	defer os.Remove(path)
+
+	t.Run("Environment", func(t *testing.T) {
+		defer os.Remove(path)
+		t.Setenv(updateEnv, "1")
+		assert.True(t, GoldenReport(t, name, errx.Wrap(2, errx.New(1, "e1")), errx.ReversedIndent))
+		t.Setenv(updateEnv, "")
+
+		data, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, "[ts 1] e1;\n  [ts 2]\n", string(data))
+		assert.True(t, GoldenReport(t, name, errx.Wrap(2, errx.New(1, "e1")), errx.ReversedIndent))
+	})
+
+	t.Run("Flag of the test package", func(t *testing.T) {
+		defer os.Remove(path)
+		*update = true
+		assert.True(t, Golden(t, name, errx.New(1, "e1")))
+		*update = false
+
+		data, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, "[ts 1] e1\n", string(data))
+	})
+}
@@ -0,0 +1,103 @@
+// Package errxzap encodes errx error chains for zap loggers with the same fields as errx's slog LogValue.
+package errxzap
+
+import (
+	"log/slog"
+
+	"github.com/michaelolof/errx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Chain is a zapcore.ObjectMarshaler for an error chain.
+// It encodes the value returned by errx.LogValue, so the fields match the ones logged through slog.
+// The value is only built when the entry is written, so fields for disabled levels cost nothing.
+type Chain struct {
+	err error
+}
+
+// Marshaler returns an ObjectMarshaler encoding the frames of err.
+func Marshaler(err error) Chain {
+	return Chain{err: err}
+}
+
+// Error returns a field named error holding the encoded error chain.
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError returns a field with the given key holding the encoded error chain.
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Marshaler(err))
+}
+
+// Logger returns an errx.Logger for errx.UseLogger that writes errors at error level through l.
+func Logger(l *zap.Logger) errx.Logger {
+	return func(err error) {
+		if err != nil {
+			l.Error(errx.CauseMessage(err), Error(err))
+		}
+	}
+}
+
+func (c Chain) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return group(errx.LogValue(c.err).Group()).MarshalLogObject(enc)
+}
+
+// group encodes the attributes of a slog group value.
+type group []slog.Attr
+
+func (g group) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		if err := addAttr(enc, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addAttr(enc zapcore.ObjectEncoder, a slog.Attr) error {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		return enc.AddObject(a.Key, group(v.Group()))
+	case slog.KindString:
+		enc.AddString(a.Key, v.String())
+	case slog.KindInt64:
+		enc.AddInt64(a.Key, v.Int64())
+	case slog.KindTime:
+		enc.AddTime(a.Key, v.Time())
+	case slog.KindDuration:
+		enc.AddDuration(a.Key, v.Duration())
+	default:
+		switch val := v.Any().(type) {
+		case []int:
+			return enc.AddArray(a.Key, ints(val))
+		case []string:
+			return enc.AddArray(a.Key, strs(val))
+		}
+		return enc.AddReflected(a.Key, v.Any())
+	}
+	return nil
+}
+
+type ints []int
+
+func (s ints) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range s {
+		enc.AppendInt(v)
+	}
+	return nil
+}
+
+type strs []string
+
+func (s strs) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range s {
+		enc.AppendString(v)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package errxzap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newLogger() (*zap.Logger, *bytes.Buffer) {
+	var b bytes.Buffer
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: zapcore.LowercaseLevelEncoder})
+	return zap.New(zapcore.NewCore(enc, zapcore.AddSync(&b), zapcore.DebugLevel)), &b
+}
+
+func decode(t *testing.T, b *bytes.Buffer) map[string]any {
+	var rtn map[string]any
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &rtn))
+	return rtn
+}
+
+func TestError(t *testing.T) {
+	err := errx.NewKind(1, errx.DataKind[int]("user_id")(42), "user missing")
+	err = fmt.Errorf("handler: %w", err)
+	err = errx.WrapKind(2, errx.DefineKind("notfound"), err)
+
+	logger, b := newLogger()
+	logger.Info("request failed", Error(err))
+
+	assert.Equal(t, map[string]any{
+		"error_stamps": []any{2.0, 1.0},
+		"error_kind":   "notfound",
+		"error_cause": map[string]any{
+			"error_stamps": []any{1.0},
+			"error_msg":    "handler:",
+			"error_cause": map[string]any{
+				"error_stamps": []any{1.0},
+				"error_kind":   "user_id",
+				"error_data":   42.0,
+				"error_msg":    "user missing",
+			},
+		},
+	}, decode(t, b)["error"])
+}
+
+func TestOccurrenceID(t *testing.T) {
+	errx.UseOccurrenceIDs(true)
+	defer errx.UseOccurrenceIDs(false)
+
+	err := errx.Wrap(2, errx.New(1, "user missing"))
+	logger, b := newLogger()
+	logger.Info("request failed", Error(err))
+
+	rec := decode(t, b)["error"].(map[string]any)
+	assert.Equal(t, errx.OccurrenceID(err), rec["error_id"])
+	assert.NotContains(t, rec["error_cause"], "error_id")
+}
+
+func TestFrameTimes(t *testing.T) {
+	now := time.Date(2025, 4, 23, 10, 0, 0, 0, time.UTC)
+	errx.RecordFrameTimes(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	defer errx.RecordFrameTimes(nil)
+
+	err := errx.Wrap(2, errx.New(1, "user missing"))
+	logger, b := newLogger()
+	logger.Info("request failed", Error(err))
+
+	rec := decode(t, b)["error"].(map[string]any)
+	assert.Equal(t, 1.745402402e+18, rec["error_time"])
+	assert.Equal(t, 1e9, rec["error_elapsed"])
+	assert.NotContains(t, rec["error_cause"], "error_elapsed")
+}
+
+func TestJoined(t *testing.T) {
+	logger, b := newLogger()
+	logger.Info("fan out failed", NamedError("err", errx.JoinWrap(10, errx.New(1, "e1"), errx.New(2, "e2"))))
+
+	rec := decode(t, b)["err"].(map[string]any)
+	assert.Equal(t, []any{10.0, 1.0, 2.0}, rec["error_stamps"])
+	assert.Equal(t, map[string]any{
+		"error_stamps": []any{1.0, 2.0},
+		"error_branches": map[string]any{
+			"0": map[string]any{"error_stamps": []any{1.0}, "error_msg": "e1"},
+			"1": map[string]any{"error_stamps": []any{2.0}, "error_msg": "e2"},
+		},
+	}, rec["error_cause"])
+}
+
+func TestNilError(t *testing.T) {
+	logger, b := newLogger()
+	logger.Info("ok", Error(nil))
+	assert.NotContains(t, decode(t, b), "error")
+}
+
+func TestLogger(t *testing.T) {
+	logger, b := newLogger()
+	errx.UseLogger(Logger(logger))
+	defer errx.UseLogger(nil)
+
+	errx.Log(errx.Wrap(2, errx.New(1, "user missing")))
+
+	rec := decode(t, b)
+	assert.Equal(t, "error", rec["level"])
+	assert.Equal(t, "user missing", rec["msg"])
+	assert.Equal(t, []any{2.0, 1.0}, rec["error"].(map[string]any)["error_stamps"])
+}
@@ -0,0 +1,64 @@
+// Package errxzerolog encodes errx error chains for zerolog loggers with the same fields as errx's slog LogValue.
+package errxzerolog
+
+import (
+	"log/slog"
+
+	"github.com/michaelolof/errx"
+	"github.com/rs/zerolog"
+)
+
+// Chain is a zerolog.LogObjectMarshaler for an error chain.
+// It encodes the value returned by errx.LogValue, so the fields match the ones logged through slog.
+// The value is only built when the event is written, so fields for disabled levels cost nothing.
+type Chain struct {
+	err error
+}
+
+// Marshaler returns a LogObjectMarshaler encoding the frames of err.
+func Marshaler(err error) Chain {
+	return Chain{err: err}
+}
+
+// Logger returns an errx.Logger for errx.UseLogger that writes errors at error level through l.
+func Logger(l zerolog.Logger) errx.Logger {
+	return func(err error) {
+		if err != nil {
+			l.Error().Object("error", Marshaler(err)).Msg(errx.CauseMessage(err))
+		}
+	}
+}
+
+func (c Chain) MarshalZerologObject(e *zerolog.Event) {
+	group(errx.LogValue(c.err).Group()).MarshalZerologObject(e)
+}
+
+// group encodes the attributes of a slog group value.
+type group []slog.Attr
+
+func (g group) MarshalZerologObject(e *zerolog.Event) {
+	for _, a := range g {
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindGroup:
+			e.Object(a.Key, group(v.Group()))
+		case slog.KindString:
+			e.Str(a.Key, v.String())
+		case slog.KindInt64:
+			e.Int64(a.Key, v.Int64())
+		case slog.KindTime:
+			e.Time(a.Key, v.Time())
+		case slog.KindDuration:
+			e.Dur(a.Key, v.Duration())
+		default:
+			switch val := v.Any().(type) {
+			case []int:
+				e.Ints(a.Key, val)
+			case []string:
+				e.Strs(a.Key, val)
+			default:
+				e.Interface(a.Key, val)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package errxzerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/michaelolof/errx"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func decode(t *testing.T, b *bytes.Buffer) map[string]any {
+	var rtn map[string]any
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &rtn))
+	return rtn
+}
+
+func TestMarshaler(t *testing.T) {
+	err := errx.NewKind(1, errx.DataKind[int]("user_id")(42), "user missing")
+	err = fmt.Errorf("handler: %w", err)
+	err = errx.WrapKind(2, errx.DefineKind("notfound"), err)
+
+	var b bytes.Buffer
+	logger := zerolog.New(&b)
+	logger.Info().Object("error", Marshaler(err)).Msg("request failed")
+
+	assert.Equal(t, map[string]any{
+		"error_stamps": []any{2.0, 1.0},
+		"error_kind":   "notfound",
+		"error_cause": map[string]any{
+			"error_stamps": []any{1.0},
+			"error_msg":    "handler:",
+			"error_cause": map[string]any{
+				"error_stamps": []any{1.0},
+				"error_kind":   "user_id",
+				"error_data":   42.0,
+				"error_msg":    "user missing",
+			},
+		},
+	}, decode(t, &b)["error"])
+}
+
+func TestOccurrenceID(t *testing.T) {
+	errx.UseOccurrenceIDs(true)
+	defer errx.UseOccurrenceIDs(false)
+
+	err := errx.Wrap(2, errx.New(1, "user missing"))
+	var b bytes.Buffer
+	logger := zerolog.New(&b)
+	logger.Info().Object("error", Marshaler(err)).Msg("request failed")
+
+	rec := decode(t, &b)["error"].(map[string]any)
+	assert.Equal(t, errx.OccurrenceID(err), rec["error_id"])
+	assert.NotContains(t, rec["error_cause"], "error_id")
+}
+
+func TestFrameTimes(t *testing.T) {
+	now := time.Date(2025, 4, 23, 10, 0, 0, 0, time.UTC)
+	errx.RecordFrameTimes(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	defer errx.RecordFrameTimes(nil)
+
+	err := errx.Wrap(2, errx.New(1, "user missing"))
+	var b bytes.Buffer
+	logger := zerolog.New(&b)
+	logger.Info().Object("error", Marshaler(err)).Msg("request failed")
+
+	rec := decode(t, &b)["error"].(map[string]any)
+	assert.Equal(t, "2025-04-23T10:00:02Z", rec["error_time"])
+	assert.Equal(t, 1000.0, rec["error_elapsed"])
+	assert.NotContains(t, rec["error_cause"], "error_elapsed")
+}
+
+func TestJoined(t *testing.T) {
+	var b bytes.Buffer
+	logger := zerolog.New(&b)
+	logger.Info().Object("err", Marshaler(errx.JoinWrap(10, errx.New(1, "e1"), errx.New(2, "e2")))).Msg("fan out failed")
+
+	rec := decode(t, &b)["err"].(map[string]any)
+	assert.Equal(t, []any{10.0, 1.0, 2.0}, rec["error_stamps"])
+	assert.Equal(t, map[string]any{
+		"error_stamps": []any{1.0, 2.0},
+		"error_branches": map[string]any{
+			"0": map[string]any{"error_stamps": []any{1.0}, "error_msg": "e1"},
+			"1": map[string]any{"error_stamps": []any{2.0}, "error_msg": "e2"},
+		},
+	}, rec["error_cause"])
+}
+
+func TestLogger(t *testing.T) {
+	var b bytes.Buffer
+	errx.UseLogger(Logger(zerolog.New(&b)))
+	defer errx.UseLogger(nil)
+
+	errx.Log(errx.Wrap(2, errx.New(1, "user missing")))
+
+	rec := decode(t, &b)
+	assert.Equal(t, "error", rec["level"])
+	assert.Equal(t, "user missing", rec["message"])
+	assert.Equal(t, []any{2.0, 1.0}, rec["error"].(map[string]any)["error_stamps"])
+}
@@ -15,6 +15,14 @@ func Must[T any](obj T, err error) T {
 	return obj
 }
 
+// MustStamp returns obj when err is nil and otherwise panics with err wrapped with the given timestamp, so the panic site is part of the stamp trace.
+func MustStamp[T any](ts lint, obj T, err error) T {
+	if err != nil {
+		panic(&StampedPanic{Err: wrapErr(ts, err)})
+	}
+	return obj
+}
+
 func Split(err error) []error {
 	if uw, ok := err.(interface{ Unwrap() []error }); ok {
 		return uw.Unwrap()
@@ -22,6 +30,21 @@ func Split(err error) []error {
 	return []error{err}
 }
 
+// StampPaths returns the stamp traces of every branch in the error tree.
+// A linear chain yields a single path while joined errors yield one path per branch, each prefixed by the stamps of the errors wrapping the join.
+func StampPaths(err error) [][]int {
+	if err == nil {
+		return nil
+	}
+
+	paths := framePaths(Frames(err))
+	rtn := make([][]int, 0, len(paths))
+	for _, path := range paths {
+		rtn = append(rtn, frameStamps(path, make([]int, 0, len(path))))
+	}
+	return rtn
+}
+
 func Contains(err error, substr string) bool {
 	if err == nil {
 		return false
@@ -68,10 +91,34 @@ func Panic(err error) {
 	}
 }
 
+// PanicStamp panics with err wrapped with the given timestamp when err is not nil.
+func PanicStamp(ts lint, err error) {
+	if err != nil {
+		panic(&StampedPanic{Err: wrapErr(ts, err)})
+	}
+}
+
+// StampedPanic is the value MustStamp and PanicStamp panic with.
+// Recovery code can detect it to get back the stamped error chain, which Recover and Safe do automatically.
+type StampedPanic struct {
+	Err error
+	// Stack is the stack of the panicking goroutine. It is only captured by Recover and Safe once enabled with CapturePanicStacks.
+	Stack []byte
+}
+
+func (p *StampedPanic) Error() string {
+	return p.Err.Error()
+}
+
+func (p *StampedPanic) Unwrap() error {
+	return p.Err
+}
+
 type stackFrame struct {
 	IsStamped bool
 	Stamp     lint
-	Kind      errKind
+	ID        string
+	Kind      Kind
 	Msg       string
 }
 
@@ -79,7 +126,7 @@ func (s stackFrame) err() *errx {
 	return stacksToErr([]stackFrame{s})
 }
 
-func newStackFrame(stampStr string, kindStr, dataStr, msg string) stackFrame {
+func newStackFrame(stampStr, idStr, kindStr, dataStr, msg string) stackFrame {
 	isUnstamped := false
 	ts, err := strconv.Atoi(stampStr)
 	if err != nil {
@@ -94,7 +141,8 @@ func newStackFrame(stampStr string, kindStr, dataStr, msg string) stackFrame {
 	return stackFrame{
 		IsStamped: !isUnstamped,
 		Stamp:     lint(ts),
-		Kind:      errKind{kind: kindStr, data: data},
+		ID:        idStr,
+		Kind:      Kind{kind: kindStr, data: data},
 		Msg:       strings.TrimSpace(msg),
 	}
 }
@@ -111,6 +159,7 @@ func getStackFrames(errStr string) []stackFrame {
 
 	type state struct {
 		stamp   string
+		id      string
 		kind    string
 		data    string
 		msg     string
@@ -129,7 +178,7 @@ func getStackFrames(errStr string) []stackFrame {
 		case openBrackets:
 			// A generic error message wrapping a stamped error
 			if len(buff.msg) > 0 {
-				frames = append(frames, newStackFrame("", "", "", strings.TrimSpace(buff.msg)))
+				frames = append(frames, newStackFrame("", "", "", "", strings.TrimSpace(buff.msg)))
 				clearBuffer()
 			}
 
@@ -138,7 +187,7 @@ func getStackFrames(errStr string) []stackFrame {
 		case wrapperDelimiter:
 			// A stamped error message wrapping another error
 			if buff.inStamp {
-				frames = append(frames, newStackFrame(buff.stamp, buff.kind, buff.data, strings.TrimSpace(buff.msg)))
+				frames = append(frames, newStackFrame(buff.stamp, buff.id, buff.kind, buff.data, strings.TrimSpace(buff.msg)))
 				clearBuffer()
 			}
 
@@ -146,13 +195,24 @@ func getStackFrames(errStr string) []stackFrame {
 			// Bring out stamp id
 			for {
 				nextToken := tree[i+1]
-				if nextToken.typ == closeBrackets || nextToken.typ == kindDirective || nextToken.typ == dataDirective {
+				if nextToken.typ == closeBrackets || nextToken.typ == idDirective || nextToken.typ == kindDirective || nextToken.typ == dataDirective {
 					break
 				}
 				buff.stamp = buff.stamp + nextToken.literal
 				i++
 			}
 
+		case idDirective:
+			// Bring out occurrence id
+			for {
+				nextToken := tree[i+1]
+				if nextToken.typ == closeBrackets || nextToken.typ == kindDirective || nextToken.typ == dataDirective {
+					break
+				}
+				buff.id = buff.id + nextToken.literal
+				i++
+			}
+
 		case kindDirective:
 			// Bring out kind error
 			for {
@@ -188,9 +248,9 @@ func getStackFrames(errStr string) []stackFrame {
 	}
 
 	if buff.inStamp {
-		frames = append(frames, newStackFrame(buff.stamp, buff.kind, buff.data, buff.msg))
+		frames = append(frames, newStackFrame(buff.stamp, buff.id, buff.kind, buff.data, buff.msg))
 	} else if buff.msg != "" {
-		frames = append(frames, newStackFrame("", "", "", buff.msg))
+		frames = append(frames, newStackFrame("", "", "", "", buff.msg))
 	}
 
 	return frames
@@ -206,15 +266,19 @@ func stacksToErr(frames []stackFrame) *errx {
 
 		switch true {
 		case frame.IsStamped && !isWrapper:
-			existingErr = newErr(frame.Stamp, frame.Msg).WithKind(frame.Kind)
+			existingErr = withKind(newErr(frame.Stamp, frame.Msg), frame.Kind)
+			existingErr.id = parsedOccurrence(frame.ID)
+			existingErr.parsed = true
 			existinge = nil
 		case frame.IsStamped && isWrapper:
 			if existinge != nil {
-				existingErr = wrapErr(frame.Stamp, existinge).WithKind(frame.Kind)
+				existingErr = withKind(wrapErr(frame.Stamp, existinge), frame.Kind)
 				existinge = nil
 			} else {
-				existingErr = wrapErr(frame.Stamp, existingErr).WithKind(frame.Kind)
+				existingErr = withKind(wrapErr(frame.Stamp, existingErr), frame.Kind)
 			}
+			existingErr.id = parsedOccurrence(frame.ID)
+			existingErr.parsed = true
 		case !frame.IsStamped && isWrapper:
 			existinge = fmt.Errorf("%s %w", frame.Msg, existingErr)
 			existingErr = nil
@@ -101,6 +101,45 @@ func TestPanic(t *testing.T) {
 	})
 }
 
+func TestMustStamp(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		assert.Equal(t, 10, MustStamp(1, 10, nil))
+	})
+
+	t.Run("Panics with the stamped error", func(t *testing.T) {
+		defer func() {
+			v := recover()
+			sp, ok := v.(*StampedPanic)
+			assert.True(t, ok)
+			assert.Equal(t, "[ts 2]; [ts 1] fail", sp.Error())
+		}()
+		MustStamp(2, 0, New(1, "fail"))
+	})
+
+	t.Run("Recovered chain", func(t *testing.T) {
+		err := Safe(3, func() error {
+			MustStamp(2, 0, New(1, "fail"))
+			return nil
+		})
+		assert.Equal(t, "[ts 3 kind panic]; [ts 2]; [ts 1] fail", err.Error())
+		assert.Equal(t, []int{3, 2, 1}, err.(*errx).Stamps())
+	})
+}
+
+func TestPanicStamp(t *testing.T) {
+	assert.NotPanics(t, func() {
+		PanicStamp(1, nil)
+	})
+
+	base := errors.New("fail")
+	err := Safe(3, func() error {
+		PanicStamp(2, base)
+		return nil
+	})
+	assert.Equal(t, "[ts 3 kind panic]; [ts 2]; fail", err.Error())
+	assert.True(t, errors.Is(err, base))
+}
+
 func TestContains(t *testing.T) {
 	err := errors.New("hello world")
 	assert.True(t, Contains(err, "hello"))
@@ -127,7 +166,7 @@ func TestCauseExtra(t *testing.T) {
 func TestCauseMessage(t *testing.T) {
 	t.Run("errx tree", func(t *testing.T) {
 		err1 := newErr(100, "base failure")
-		err := wrapErr(200, err1).WithKind(Kind("some_kind"))
+		err := wrapErr(200, err1).WithKind(DefineKind("some_kind"))
 		err = wrapErr(300, err)
 		assert.Equal(t, "base failure", CauseMessage(err))
 	})
@@ -135,20 +174,20 @@ func TestCauseMessage(t *testing.T) {
 	t.Run("errx tree with wrapped formatted fmt.Errorf", func(t *testing.T) {
 		stdErr := fmt.Errorf("formatting user %s failed", "John")
 		err1 := fmt.Errorf("wrapped context: %w", stdErr)
-		err := wrapErr(100, err1).WithKind(Kind("wrap_kind"))
+		err := wrapErr(100, err1).WithKind(DefineKind("wrap_kind"))
 		assert.Equal(t, "formatting user John failed", CauseMessage(err))
 	})
 
 	t.Run("errx tree with nested stamped error containing kind and data", func(t *testing.T) {
 		err1 := newErr(100, "deepest stamped failure").WithKind(DataKind[int]("numeric_fault")(42))
-		err := wrapErr(200, err1).WithKind(Kind("middle_kind"))
+		err := wrapErr(200, err1).WithKind(DefineKind("middle_kind"))
 		err = wrapErr(300, err)
 		assert.Equal(t, "deepest stamped failure", CauseMessage(err))
 	})
 
 	t.Run("errx stringified and parsed back", func(t *testing.T) {
 		err1 := newErr(100, "original base message").WithKind(DataKind[string]("db_error")("connection lost"))
-		err := wrapErr(200, err1).WithKind(Kind("api_fault"))
+		err := wrapErr(200, err1).WithKind(DefineKind("api_fault"))
 		err = wrapErr(300, err)
 
 		formattedStr := err.Error()
@@ -160,7 +199,7 @@ func TestCauseMessage(t *testing.T) {
 	t.Run("errx wrapping fmt.Errorf", func(t *testing.T) {
 		stdErr := errors.New("standard error")
 		err1 := fmt.Errorf("wrapped context: %w", stdErr)
-		err := wrapErr(100, err1).WithKind(Kind("wrap_kind"))
+		err := wrapErr(100, err1).WithKind(DefineKind("wrap_kind"))
 		assert.Equal(t, "standard error", CauseMessage(err))
 	})
 
@@ -173,3 +212,24 @@ func TestCauseMessage(t *testing.T) {
 		assert.Equal(t, "", CauseMessage(nil))
 	})
 }
+
+func TestStampPaths(t *testing.T) {
+	t.Run("Linear chain", func(t *testing.T) {
+		err := Wrap(3, Wrap(2, New(1, "e1")))
+		assert.Equal(t, [][]int{{3, 2, 1}}, StampPaths(err))
+	})
+
+	t.Run("Joined branches", func(t *testing.T) {
+		err := JoinWrap(10,
+			Wrap(11, New(1, "e1")),
+			fmt.Errorf("generic: %w", New(2, "e2")),
+			errors.New("e3"),
+		)
+		assert.Equal(t, [][]int{{10, 11, 1}, {10, 2}, {10}}, StampPaths(err))
+		assert.Equal(t, []int{10, 11, 1, 2}, err.(*errx).Stamps())
+	})
+
+	t.Run("Nil error", func(t *testing.T) {
+		assert.Nil(t, StampPaths(nil))
+	})
+}
@@ -0,0 +1,250 @@
+package errx
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Frame is a single level of an error chain as returned by Frames.
+type Frame struct {
+	// Stamp is the stamp of the frame or 0 if the frame is not stamped.
+	Stamp int
+	// Kind is the name of the error kind attached to the frame.
+	Kind string
+	// Data is the data attached to the frame's kind.
+	// It holds the native value for live errors and the raw string for parsed errors.
+	Data any
+	// ID is the occurrence ID held by the frame. Only the root errx error of a chain holds one.
+	ID string
+	// Time is the time the frame was created. It is only recorded once enabled with RecordFrameTimes.
+	Time time.Time
+	// Msg is the message of the frame without the messages of the errors it wraps.
+	Msg string
+	// Err is the error the frame was read from.
+	Err error
+	// Branches holds the frames of every joined error when Err implements Unwrap() []error or is combined by an Adapter.
+	Branches [][]Frame
+	// Stack is the call stack recorded by foreign errors recognized by an Adapter.
+	Stack []runtime.Frame
+
+	kind Kind
+}
+
+// Returns the string representation of the frame alone, without the frames it wraps.
+// Frames with a stack end with the function and file they were created in.
+func (f Frame) String() string {
+	rtn := stampDetails(lint(f.Stamp), f.ID, f.kind)
+	if rtn == "" {
+		rtn = f.Msg
+	} else if f.Msg != "" {
+		rtn = rtn + " " + f.Msg
+	}
+
+	if len(f.Stack) > 0 {
+		at := fmt.Sprintf("(at %s %s:%d)", f.Stack[0].Function, filepath.Base(f.Stack[0].File), f.Stack[0].Line)
+		if rtn == "" {
+			return at
+		}
+		return rtn + " " + at
+	}
+	return rtn
+}
+
+// Returns the stack of the frame with one function file:line entry per call.
+func (f Frame) StackTrace() []string {
+	rtn := make([]string, 0, len(f.Stack))
+	for _, call := range f.Stack {
+		rtn = append(rtn, fmt.Sprintf("%s %s:%d", call.Function, call.File, call.Line))
+	}
+	return rtn
+}
+
+// Returns the data of the frame as rendered in the error string.
+func (f Frame) DataString() string {
+	return f.kind.data.String()
+}
+
+// FrameData returns the data of the frame as T. The raw string held by frames of parsed errors is decoded into T.
+func FrameData[T DataType](f Frame) (*T, bool) {
+	if !f.kind.data.isSet {
+		return nil, false
+	} else if f.kind.data.val != nil {
+		if v, ok := f.kind.data.val.(T); ok {
+			return &v, true
+		}
+		return nil, false
+	} else if f.kind.data.valStr != "" {
+		if v, err := fromStr[T](f.kind.data.valStr); err == nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Frames walks the error chain and returns one frame per level, from the outermost error to the root cause.
+// errx errors are read structurally while foreign errors are walked using Unwrap and the adapters registered with RegisterAdapter.
+// When a joined error is reached it is returned as the last frame with each joined error walked into Branches.
+// The redaction policy configured with UseRedaction is applied to the kind data and messages of every frame.
+func Frames(err error) []Frame {
+	return walkFrames(err, currentRedaction())
+}
+
+func walkFrames(err error, policy *RedactPolicy) []Frame {
+	frames := make([]Frame, 0, 10)
+	// pending holds a foreign frame that only records a stack, such as pkg/errors' withStack.
+	// Its stack is merged into the next foreign frame that has none of its own.
+	var pending *Frame
+	flush := func() {
+		if pending != nil {
+			frames = append(frames, *pending)
+			pending = nil
+		}
+	}
+	merge := func(frame Frame) Frame {
+		if pending != nil && len(frame.Stack) == 0 {
+			frame.Stack = pending.Stack
+			pending = nil
+		}
+		flush()
+		return frame
+	}
+
+	for err != nil {
+		if e, ok := err.(*errx); ok {
+			if e == nil {
+				break
+			}
+			flush()
+			if e.ts != 0 || e.kind.kind != "" || e.kind.data.isSet || e.msg != "" {
+				frames = append(frames, newFrame(e, policy))
+			}
+			err = e.Unwrap()
+			continue
+		}
+
+		adapted, _ := adapt(err)
+		cause, joined := nextErrors(err, adapted)
+		if joined != nil {
+			frame := foreignFrame(err, "")
+			frame.Stack = adapted.Stack
+			for _, e := range joined {
+				if e != nil {
+					frame.Branches = append(frame.Branches, walkFrames(e, policy))
+				}
+			}
+			return append(frames, merge(frame))
+		}
+
+		if cause == nil {
+			frame := foreignFrame(err, strings.TrimSpace(err.Error()))
+			frame.Stack = adapted.Stack
+			frames = append(frames, merge(frame))
+			break
+		}
+
+		frame := foreignFrame(err, ownMessage(err.Error(), cause))
+		frame.Stack = adapted.Stack
+		if frame.Stamp != 0 || frame.Kind != "" || frame.Msg != "" {
+			frames = append(frames, merge(frame))
+		} else if len(frame.Stack) > 0 {
+			flush()
+			pending = &frame
+		}
+		err = cause
+	}
+	flush()
+	return frames
+}
+
+func newFrame(e *errx, policy *RedactPolicy) Frame {
+	kind, msg := e.redacted(policy)
+	frame := Frame{
+		Stamp: int(e.ts),
+		Kind:  kind.kind,
+		ID:    e.id.String(),
+		Time:  unixTime(e.at),
+		Msg:   msg,
+		Err:   e,
+		kind:  kind,
+	}
+	if kind.data.isSet {
+		if kind.data.val != nil {
+			frame.Data = kind.data.val
+		} else {
+			frame.Data = kind.data.valStr
+		}
+	}
+	return frame
+}
+
+func foreignFrame(err error, msg string) Frame {
+	frame := Frame{Msg: msg, Err: err}
+	if v, ok := err.(interface{ Stamp() int }); ok {
+		frame.Stamp = v.Stamp()
+	}
+	if v, ok := err.(interface{ Kind() string }); ok {
+		frame.Kind = v.Kind()
+		frame.kind = Kind{kind: frame.Kind}
+	}
+	return frame
+}
+
+// ownMessage strips the wrapped error's message from the wrapper's message.
+// A foreign wrapper keeps the string its cause rendered when it was created, which is the unredacted one when
+// the redaction policy was configured later, so the unredacted string of an errx cause is tried as well.
+func ownMessage(msg string, wrapped error) string {
+	if own, ok := stripMessage(msg, wrapped.Error()); ok {
+		return own
+	} else if e, ok := wrapped.(*errx); ok {
+		var b strings.Builder
+		e.renderChain(&b, nil, "")
+		if own, ok := stripMessage(msg, b.String()); ok {
+			return own
+		}
+	}
+	return strings.TrimSpace(msg)
+}
+
+func stripMessage(msg, wrapped string) (string, bool) {
+	if strings.HasSuffix(msg, wrapped) {
+		return strings.TrimSpace(msg[:len(msg)-len(wrapped)]), true
+	} else if before, after, found := strings.Cut(msg, wrapped); found {
+		return strings.TrimSpace(before + after), true
+	}
+	return "", false
+}
+
+// framePaths flattens the frames into one list of frames per joined branch, from the outermost frame to the branch's root cause.
+func framePaths(frames []Frame) [][]Frame {
+	if len(frames) == 0 || len(frames[len(frames)-1].Branches) == 0 {
+		return [][]Frame{frames}
+	}
+
+	prefix := frames[:len(frames)-1]
+	branches := frames[len(frames)-1].Branches
+	rtn := make([][]Frame, 0, len(branches))
+	for _, branch := range branches {
+		for _, path := range framePaths(branch) {
+			joined := make([]Frame, 0, len(prefix)+len(path))
+			joined = append(joined, prefix...)
+			joined = append(joined, path...)
+			rtn = append(rtn, joined)
+		}
+	}
+	return rtn
+}
+
+func frameStamps(frames []Frame, rtn []int) []int {
+	for _, frame := range frames {
+		if frame.Stamp != 0 {
+			rtn = append(rtn, frame.Stamp)
+		}
+		for _, branch := range frame.Branches {
+			rtn = frameStamps(branch, rtn)
+		}
+	}
+	return rtn
+}
@@ -0,0 +1,117 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrames(t *testing.T) {
+	t.Run("errx chain", func(t *testing.T) {
+		err := NewKind(1, DataKind[int]("count")(3), "e1")
+		err = Wrap(2, err)
+		err = WrapKind(3, DefineKind("outer"), err)
+
+		frames := Frames(err)
+		assert.Len(t, frames, 3)
+		assert.Equal(t, 3, frames[0].Stamp)
+		assert.Equal(t, "outer", frames[0].Kind)
+		assert.Equal(t, "[ts 3 kind outer]", frames[0].String())
+		assert.Equal(t, 2, frames[1].Stamp)
+		assert.Equal(t, 1, frames[2].Stamp)
+		assert.Equal(t, "count", frames[2].Kind)
+		assert.Equal(t, 3, frames[2].Data)
+		assert.Equal(t, "e1", frames[2].Msg)
+		assert.Equal(t, "[ts 1 kind count data 3] e1", frames[2].String())
+	})
+
+	t.Run("Foreign errors in chain", func(t *testing.T) {
+		err := errors.New("root")
+		err = fmt.Errorf("context: %w", err)
+		err = Wrap(1, err)
+
+		frames := Frames(err)
+		assert.Len(t, frames, 3)
+		assert.Equal(t, 1, frames[0].Stamp)
+		assert.Equal(t, "context:", frames[1].Msg)
+		assert.Equal(t, "root", frames[2].Msg)
+	})
+
+	t.Run("Message repeating the child's text", func(t *testing.T) {
+		inner := errors.New("failed")
+		err := fmt.Errorf("failed: %w", inner)
+
+		frames := Frames(err)
+		assert.Len(t, frames, 2)
+		assert.Equal(t, "failed:", frames[0].Msg)
+		assert.Equal(t, "failed", frames[1].Msg)
+	})
+
+	t.Run("Parsed errors", func(t *testing.T) {
+		err := NewKind(1, DataKind[string]("url")("www.test.com"), "e1")
+		err = Wrap(2, err)
+
+		frames := Frames(ParseStampedError(err.Error()))
+		assert.Len(t, frames, 2)
+		assert.Equal(t, `"www.test.com"`, frames[1].Data)
+		assert.Equal(t, `[ts 1 kind url data "www.test.com"] e1`, frames[1].String())
+	})
+
+	t.Run("Joined errors", func(t *testing.T) {
+		err := JoinWrap(10, New(1, "e1"), New(2, "e2"))
+
+		frames := Frames(err)
+		assert.Len(t, frames, 2)
+		assert.Equal(t, 10, frames[0].Stamp)
+		assert.Len(t, frames[1].Branches, 2)
+		assert.Equal(t, 1, frames[1].Branches[0][0].Stamp)
+		assert.Equal(t, 2, frames[1].Branches[1][0].Stamp)
+	})
+
+	t.Run("Nil error", func(t *testing.T) {
+		assert.Empty(t, Frames(nil))
+	})
+}
+
+func TestLogValue(t *testing.T) {
+	err := NewKind(1, DefineKind("notfound"), "e1")
+	err = Wrap(2, fmt.Errorf("context: %w", err))
+
+	val := err.(*errx).LogValue()
+	attrs := val.Group()
+	assert.Equal(t, "error_stamps", attrs[0].Key)
+	assert.Equal(t, []int{2, 1}, attrs[0].Value.Any())
+	assert.Equal(t, "error_cause", attrs[1].Key)
+
+	cause := attrs[1].Value.Group()
+	assert.Equal(t, slog.String("error_msg", "context:"), cause[1])
+	assert.Equal(t, "error_cause", cause[2].Key)
+
+	root := cause[2].Value.Group()
+	assert.Equal(t, slog.String("error_kind", "notfound"), root[1])
+	assert.Equal(t, slog.String("error_msg", "e1"), root[2])
+}
+
+func TestFrameData(t *testing.T) {
+	kind := DataKind[[]int]("ids")
+	err := Wrap(2, NewKind(1, kind([]int{1, 2}), "e1"))
+
+	live := Frames(err)[1]
+	parsed := Frames(ParseStampedError(err.Error()))[1]
+
+	for _, frame := range []Frame{live, parsed} {
+		data, ok := FrameData[[]int](frame)
+		assert.True(t, ok)
+		assert.Equal(t, []int{1, 2}, *data)
+		assert.Equal(t, "[1,2]", frame.DataString())
+
+		_, ok = FrameData[string](frame)
+		assert.False(t, ok)
+	}
+
+	_, ok := FrameData[int](Frames(err)[0])
+	assert.False(t, ok)
+}
@@ -0,0 +1,49 @@
+package errx
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var _frameClock atomic.Pointer[func() time.Time]
+
+// RecordFrameTimes enables recording the time every errx frame is created using the given clock, such as time.Now.
+// The times show how long passed between the root failure and each wrap, which helps diagnosing slow propagation through retries and timeouts.
+// Passing nil disables recording.
+func RecordFrameTimes(now func() time.Time) {
+	if now == nil {
+		_frameClock.Store(nil)
+		return
+	}
+	_frameClock.Store(&now)
+}
+
+// Returns the time the error frame was created or the zero time when frame times were not recorded.
+func (e *errx) Time() time.Time {
+	return unixTime(e.at)
+}
+
+// frameTime returns the current time of the configured clock in unix nanoseconds or 0 when frame times are not recorded.
+func frameTime() int64 {
+	if now := _frameClock.Load(); now != nil {
+		return (*now)().UnixNano()
+	}
+	return 0
+}
+
+func unixTime(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// rootTime returns the time of the innermost frame with a recorded time.
+func rootTime(frames []Frame) time.Time {
+	for i := len(frames) - 1; i >= 0; i-- {
+		if !frames[i].Time.IsZero() {
+			return frames[i].Time
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,75 @@
+package errx
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stepClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestFrameTimes(t *testing.T) {
+	start := time.Date(2025, 4, 23, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Disabled", func(t *testing.T) {
+		err := NewBuild(1, "user missing")
+		assert.True(t, err.Time().IsZero())
+		assert.True(t, Frames(err)[0].Time.IsZero())
+	})
+
+	clock := &stepClock{now: start, step: time.Second}
+	RecordFrameTimes(clock.Now)
+	defer RecordFrameTimes(nil)
+
+	t.Run("Recorded per frame", func(t *testing.T) {
+		root := New(1, "user missing")
+		clock.step = 3 * time.Second
+		err := Wrap(3, Wrap(2, root))
+
+		frames := Frames(err)
+		assert.Equal(t, start.Add(7*time.Second), frames[0].Time.UTC())
+		assert.Equal(t, start.Add(4*time.Second), frames[1].Time.UTC())
+		assert.Equal(t, start.Add(time.Second), frames[2].Time.UTC())
+		assert.Equal(t, frames[2].Time, root.(*errx).Time())
+		assert.Equal(t, "[ts 3]; [ts 2]; [ts 1] user missing", err.Error())
+	})
+
+	t.Run("Kept by copies", func(t *testing.T) {
+		root := NewBuild(1, "user missing")
+		assert.Equal(t, root.Time(), root.WithKind(DefineKind("notfound")).Time())
+		assert.Equal(t, root.Time(), Redact(root, RedactPolicy{}).(*errx).Time())
+	})
+
+	t.Run("LogValue", func(t *testing.T) {
+		clock.now, clock.step = start, time.Second
+		err := Wrap(3, Wrap(2, errors.New("io failure")))
+
+		attrs := err.(slog.LogValuer).LogValue().Group()
+		top := attrMap(attrs)
+		assert.Equal(t, start.Add(2*time.Second), top["error_time"].Time().UTC())
+		assert.Equal(t, time.Second, top["error_elapsed"].Duration())
+
+		cause := attrMap(top["error_cause"].Group())
+		assert.Equal(t, start.Add(time.Second), cause["error_time"].Time().UTC())
+		assert.NotContains(t, cause, "error_elapsed")
+	})
+}
+
+func attrMap(attrs []slog.Attr) map[string]slog.Value {
+	rtn := make(map[string]slog.Value, len(attrs))
+	for _, a := range attrs {
+		rtn[a.Key] = a.Value
+	}
+	return rtn
+}
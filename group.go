@@ -0,0 +1,108 @@
+package errx
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Group runs functions in goroutines and collects every error they return.
+// Unlike errgroup it keeps all failures, joining them under the group's stamp so each keeps its own stamp trace.
+type Group struct {
+	ts        lint
+	cancel    context.CancelCauseFunc
+	maxErrors int
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	next    int
+	errs    []groupErr
+	dropped int
+}
+
+type groupErr struct {
+	idx int
+	err error
+}
+
+// NewGroup returns a group whose collected errors are wrapped with the given stamp.
+func NewGroup(ts lint) *Group {
+	return &Group{ts: ts}
+}
+
+// GroupWithContext returns a group and a context derived from ctx that is canceled as soon as a function fails.
+// The context's cause is the first error returned.
+func GroupWithContext(ctx context.Context, ts lint) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{ts: ts, cancel: cancel}, ctx
+}
+
+// SetMaxErrors limits the number of errors kept by the group. Errors past the limit are only counted and written through the logger configured with UseLogger.
+// A limit of zero or less keeps every error.
+func (g *Group) SetMaxErrors(n int) {
+	g.maxErrors = n
+}
+
+// Go calls the given function in a new goroutine and collects its error.
+func (g *Group) Go(fn func() error) {
+	g.mu.Lock()
+	idx := g.next
+	g.next++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.collect(idx, err)
+		}
+	}()
+}
+
+func (g *Group) collect(idx int, err error) {
+	g.mu.Lock()
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+	dropped := g.maxErrors > 0 && len(g.errs) >= g.maxErrors
+	if dropped {
+		g.dropped++
+	} else {
+		g.errs = append(g.errs, groupErr{idx: idx, err: err})
+	}
+	g.mu.Unlock()
+
+	// Dropped errors are logged outside the lock so a slow logger doesn't hold up the other goroutines
+	if dropped {
+		Log(err)
+	}
+}
+
+// Wait blocks until every function has returned and returns the collected errors joined under the group's stamp, in the order the functions were started.
+// It returns nil when no function failed.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(g.errs, func(a, b groupErr) int {
+		return a.idx - b.idx
+	})
+	errs := make([]error, 0, len(g.errs)+1)
+	for _, e := range g.errs {
+		errs = append(errs, e.err)
+	}
+	if g.dropped > 0 {
+		errs = append(errs, fmt.Errorf("%d more errors omitted", g.dropped))
+	}
+	return JoinWrap(g.ts, errs...)
+}
@@ -0,0 +1,82 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("Collects every error", func(t *testing.T) {
+		g := NewGroup(100)
+		g.Go(func() error { return Wrap(11, New(1, "call one failed")) })
+		g.Go(func() error { return nil })
+		g.Go(func() error { return Wrap(13, New(3, "call three failed")) })
+		g.Go(func() error { return errors.New("call four failed") })
+
+		err := g.Wait()
+		assert.Equal(t, [][]int{{100, 11, 1}, {100, 13, 3}, {100}}, StampPaths(err))
+		assert.Equal(t, "[ts 100]; [ts 11]; [ts 1] call one failed\n[ts 13]; [ts 3] call three failed\ncall four failed", err.Error())
+	})
+
+	t.Run("No errors", func(t *testing.T) {
+		g := NewGroup(100)
+		g.Go(func() error { return nil })
+		assert.Nil(t, g.Wait())
+	})
+
+	t.Run("Max errors", func(t *testing.T) {
+		g := NewGroup(100)
+		g.SetMaxErrors(2)
+		for range 5 {
+			g.Go(func() error { return New(1, "failed") })
+		}
+
+		err := g.Wait()
+		errs := Split(Unwrap(err))
+		assert.Len(t, errs, 3)
+		assert.Equal(t, "3 more errors omitted", errs[2].Error())
+	})
+
+	t.Run("Dropped errors are logged", func(t *testing.T) {
+		var mu sync.Mutex
+		var logged []error
+		UseLogger(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			logged = append(logged, err)
+		})
+		defer UseLogger(nil)
+
+		g := NewGroup(100)
+		g.SetMaxErrors(1)
+		for range 3 {
+			g.Go(func() error { return New(1, "failed") })
+		}
+		g.Wait()
+		assert.Len(t, logged, 2)
+	})
+
+	t.Run("Cancel on first error", func(t *testing.T) {
+		g, ctx := GroupWithContext(context.Background(), 100)
+		first := New(1, "first failure")
+
+		var canceled atomic.Bool
+		g.Go(func() error { return first })
+		g.Go(func() error {
+			<-ctx.Done()
+			canceled.Store(true)
+			return Wrap(2, context.Cause(ctx))
+		})
+
+		err := g.Wait()
+		assert.True(t, canceled.Load())
+		assert.True(t, errors.Is(context.Cause(ctx), first))
+		assert.True(t, strings.HasPrefix(err.Error(), "[ts 100]; [ts 1] first failure\n[ts 2]; [ts 1] first failure"))
+	})
+}
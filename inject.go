@@ -0,0 +1,38 @@
+package errx
+
+import (
+	"github.com/michaelolof/errx/internal/fault"
+)
+
+// Inject returns the fault registered for the stamp with errxtest.FailAt or errxtest.FailWith, or nil when there is none.
+// It lets tests force rarely hit error paths at a given stamp deterministically and costs a single atomic load otherwise.
+//
+//	if err := errx.Inject(1745397000); err != nil {
+//		return err
+//	}
+func Inject(ts lint) error {
+	v, ok := fault.Get(int(ts))
+	if !ok {
+		return nil
+	}
+
+	switch f := v.(type) {
+	case Kind:
+		return withKind(newErr(ts, "injected fault"), f)
+	case error:
+		return wrapErr(ts, f)
+	}
+	return newErr(ts, "injected fault")
+}
+
+// Check wraps err with the given timestamp, or returns the fault registered for the stamp when err is nil.
+//
+//	if err := errx.Check(1745397000, db.Ping()); err != nil {
+//		return err
+//	}
+func Check(ts lint, err error) error {
+	if err != nil {
+		return wrapErr(ts, err)
+	}
+	return Inject(ts)
+}
@@ -0,0 +1,62 @@
+// Package fault holds the faults registered by errxtest for errx.Inject.
+// It lives in an internal package so only tests going through errxtest can register faults.
+package fault
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	active atomic.Int64
+	mu     sync.RWMutex
+	// faults holds the registrations of every stamp, the latest last
+	faults = make(map[int][]*registration)
+)
+
+// registration is a single call to Set. It is stored by pointer so removing a fault compares registrations rather than fault values,
+// which may not be comparable and may be registered more than once.
+type registration struct {
+	fault any
+}
+
+// Set registers a fault for the given stamp and returns a function removing it.
+// The fault is an error kind or an error, interpreted by errx.Inject.
+// A later registration for the same stamp overrides the fault until it is removed, which restores the previous one,
+// so a subtest can override the fault of its parent test.
+func Set(ts int, fault any) (remove func()) {
+	reg := &registration{fault: fault}
+	mu.Lock()
+	faults[ts] = append(faults[ts], reg)
+	mu.Unlock()
+	active.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			regs := slices.DeleteFunc(faults[ts], func(r *registration) bool { return r == reg })
+			if len(regs) == 0 {
+				delete(faults, ts)
+			} else {
+				faults[ts] = regs
+			}
+			active.Add(-1)
+		})
+	}
+}
+
+// Get returns the fault registered last for the given stamp. It is a single atomic load when no fault is registered.
+func Get(ts int) (any, bool) {
+	if active.Load() == 0 {
+		return nil, false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if regs := faults[ts]; len(regs) > 0 {
+		return regs[len(regs)-1].fault, true
+	}
+	return nil, false
+}
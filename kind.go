@@ -4,24 +4,74 @@ import (
 	"encoding/json"
 )
 
-// Define a basic error kind
-func Kind(k string) errKind {
-	return errKind{
+// Define a basic error kind. Metadata passed along is recorded in the kind registry and can be resolved with KindInfo
+func DefineKind(k string, meta ...KindMeta) Kind {
+	registerKind(k, meta)
+	return Kind{
 		kind: k,
 		data: dataValue{isSet: false},
 	}
 }
 
-// Define an error kind with acceptable data types
-func DataKind[T DataType](k string) func(d T) errKind {
-	return func(d T) errKind {
-		return errKind{
+// Define an error kind with acceptable data types. Metadata passed along is recorded in the kind registry and can be resolved with KindInfo
+func DataKind[T DataType](k string, meta ...KindMeta) func(d T) Kind {
+	registerKind(k, meta)
+	return func(d T) Kind {
+		return Kind{
 			kind: k,
 			data: dataValue{isSet: true, val: d},
 		}
 	}
 }
 
+// Returns the name of the error kind
+func (k Kind) Name() string {
+	return k.kind
+}
+
+// New returns a timestamped error with a message and the error kind
+func (k Kind) New(ts lint, msg string) error {
+	return withKind(newErr(ts, msg), k)
+}
+
+// Newf returns a timestamped error with the error kind and the message formatted according to a format specifier
+func (k Kind) Newf(ts lint, pattern string, a ...any) error {
+	return withKind(newErrf(ts, pattern, a...), k)
+}
+
+// Wrap wraps an existing error given the timestamp and the error kind
+func (k Kind) Wrap(ts lint, err error) error {
+	return withKind(wrapErr(ts, err), k)
+}
+
+// Wrapf wraps an existing error given the timestamp and the error kind and formats the existing error message according to the format specifier defined
+func (k Kind) Wrapf(ts lint, pattern string, err error, a ...any) error {
+	return withKind(wrapErrf(ts, pattern, err, a...), k)
+}
+
+// Is reports whether any error in the chain, including joined and adapted errors, has the error kind
+func (k Kind) Is(err error) bool {
+	return hasKind(err, k.kind)
+}
+
+// Marks an error kind as sensitive so its data is redacted by the configured redaction policy
+func Sensitive(kind Kind) Kind {
+	kind.sensitive = true
+	return kind
+}
+
+// Define a sensitive error kind with acceptable data types. Its data is redacted by the configured redaction policy
+func SensitiveDataKind[T DataType](k string, meta ...KindMeta) func(d T) Kind {
+	registerKind(k, meta)
+	return func(d T) Kind {
+		return Kind{
+			kind:      k,
+			data:      dataValue{isSet: true, val: d},
+			sensitive: true,
+		}
+	}
+}
+
 type dataValue struct {
 	isSet  bool
 	val    any
@@ -48,56 +98,55 @@ type DataType interface {
 		~map[int]int | ~map[int]float32 | ~map[int]float64 | ~map[int]string
 }
 
-func IsKind(err error, kind errKind) bool {
-	for err != nil {
-		if e, ok := err.(interface{ Kind() string }); ok {
-			if e.Kind() == kind.kind {
-				return true
-			}
-		}
-		err = Unwrap(err)
-	}
-	return false
+// IsKind reports whether any error in the chain, including joined and adapted errors, has the given kind
+func IsKind(err error, kind Kind) bool {
+	return kind.Is(err)
 }
 
-func IsDataKind[T DataType](err error, kind func(d T) errKind) bool {
+// IsDataKind reports whether any error in the chain, including joined and adapted errors, has the given data kind
+func IsDataKind[T DataType](err error, kind func(d T) Kind) bool {
 	var d T
-	for err != nil {
+	return hasKind(err, kind(d).kind)
+}
+
+func hasKind(err error, kind string) bool {
+	classifying := _classifyStd.Load()
+	return findErr(err, func(err error) bool {
 		if e, ok := err.(interface{ Kind() string }); ok {
-			if e.Kind() == kind(d).kind {
-				return true
-			}
+			return e.Kind() == kind
+		} else if classifying {
+			k, ok := classify(err)
+			return ok && k.kind == kind
 		}
-		err = Unwrap(err)
-	}
-	return false
+		return false
+	})
 }
 
 // Unwraps the error and retrieves the data values and returns the first one that matches the specified error kind and given type
-func FindData[T DataType](err error, kind func(T) errKind) (*T, bool) {
+func FindData[T DataType](err error, kind func(T) Kind) (*T, bool) {
 	var dv T
 	k := kind(dv)
-	for err != nil {
-		switch t := err.(type) {
-		case *errx:
-			if k.kind == t.kind.kind && t.kind.data.isSet && t.kind.data.val != nil {
-				if v, ok := t.kind.data.val.(T); ok {
-					return &v, true
-				} else {
-					return nil, false
-				}
-			} else if k.kind == t.kind.kind && t.kind.data.isSet && t.kind.data.valStr != "" {
-				var d T
-				if err := json.Unmarshal([]byte(t.kind.data.valStr), &d); err == nil {
-					return &d, true
-				} else {
-					return nil, false
-				}
+
+	var rtn *T
+	findErr(err, func(err error) bool {
+		t, ok := err.(*errx)
+		if !ok || k.kind != t.kind.kind || !t.kind.data.isSet {
+			return false
+		}
+		if t.kind.data.val != nil {
+			if v, ok := t.kind.data.val.(T); ok {
+				rtn = &v
 			}
+			return true
+		} else if t.kind.data.valStr != "" {
+			var d T
+			if err := json.Unmarshal([]byte(t.kind.data.valStr), &d); err == nil {
+				rtn = &d
+			}
+			return true
 		}
+		return false
+	})
 
-		err = Unwrap(err)
-	}
-
-	return nil, false
+	return rtn, rtn != nil
 }
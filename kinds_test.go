@@ -8,7 +8,6 @@ import (
 )
 
 var (
-	NotFound  = Kind("notfound")
 	FileOpen  = DataKind[string]("fileopen")
 	PageLoad  = DataKind[string]("pageload")
 	UserLogin = DataKind[UserInfo]("userlogin")
@@ -101,3 +100,46 @@ func TestOtherTypes(t *testing.T) {
 		assert.Equal(t, map[string]int{"a": 1}, *res3)
 	})
 }
+
+func TestKindMethods(t *testing.T) {
+	t.Run("Constructors", func(t *testing.T) {
+		err := NotFound.New(1, "user missing")
+		assert.Equal(t, NewKind(1, NotFound, "user missing").Error(), err.Error())
+		assert.Equal(t, "[ts 1 kind notfound] user missing", err.Error())
+
+		err = NotFound.Newf(2, "user %d missing", 7)
+		assert.Equal(t, "[ts 2 kind notfound] user 7 missing", err.Error())
+
+		err = Invalid.Wrap(3, err)
+		assert.Equal(t, WrapKind(3, Invalid, NotFound.Newf(2, "user %d missing", 7)).Error(), err.Error())
+		assert.Equal(t, "[ts 3 kind invalid]; [ts 2 kind notfound] user 7 missing", err.Error())
+
+		err = Invalid.Wrapf(4, "lookup: %v", NotFound.New(1, "user missing"))
+		assert.Equal(t, "[ts 4 kind invalid]; [ts 1 kind notfound] lookup: user missing", err.Error())
+	})
+
+	t.Run("Data Kinds", func(t *testing.T) {
+		err := FileOpen("a.txt").Wrap(1, New(2, "denied"))
+		res, ok := FindData(err, FileOpen)
+		assert.True(t, ok)
+		assert.Equal(t, "a.txt", *res)
+		assert.True(t, FileOpen("b.txt").Is(err))
+	})
+
+	t.Run("Is", func(t *testing.T) {
+		err := Wrap(2, NotFound.New(1, "user missing"))
+		assert.True(t, NotFound.Is(err))
+		assert.False(t, Invalid.Is(err))
+		assert.Equal(t, IsKind(err, NotFound), NotFound.Is(err))
+		assert.False(t, NotFound.Is(nil))
+	})
+
+	t.Run("Map Keys", func(t *testing.T) {
+		statuses := map[Kind]int{NotFound: 404, Invalid: 400}
+		assert.Equal(t, 404, statuses[NotFound])
+		assert.Equal(t, "notfound", NotFound.Name())
+
+		var kind Kind = Invalid
+		assert.Equal(t, 400, statuses[kind])
+	})
+}
@@ -0,0 +1,272 @@
+package errx
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	logfmtStamps = "err_stamps"
+	logfmtID     = "err_id"
+	logfmtKind   = "err_kind"
+	logfmtData   = "err_data"
+	logfmtMsg    = "err_msg"
+)
+
+// logfmt renders the error chain as a single line of logfmt pairs:
+//
+//	err_stamps=3,2,1 err_kind=notfound err_data.id=42 err_msg="user not found"
+//
+// err_stamps holds the stamp trace, walking joined errors depth first. err_id holds the occurrence ID when the error has one.
+// err_kind and err_data come from the outermost frame with a kind. Object data is flattened into one err_data.<key> pair per key.
+// err_msg holds the message of the root cause with the redaction policy applied.
+func logfmt(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	frames := Frames(err)
+	var b strings.Builder
+
+	if stamps := frameStamps(frames, nil); len(stamps) > 0 {
+		b.WriteString(logfmtStamps)
+		b.WriteByte('=')
+		for i, ts := range stamps {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(ts))
+		}
+	}
+
+	if id := framesOccurrenceID(frames); id != "" {
+		writeLogfmtPair(&b, logfmtID, id)
+	}
+
+	if frame, ok := firstKindFrame(frames); ok {
+		writeLogfmtPair(&b, logfmtKind, frame.Kind)
+		if frame.kind.data.isSet {
+			writeLogfmtData(&b, frame.DataString())
+		}
+	}
+
+	writeLogfmtPair(&b, logfmtMsg, rootMessage(frames))
+	return b.String()
+}
+
+// rootMessage returns the message of the root cause from frames that already have the redaction policy applied.
+// A root joining several errors has no message of its own, so its error string is used.
+func rootMessage(frames []Frame) string {
+	if len(frames) == 0 {
+		return ""
+	}
+	root := frames[len(frames)-1]
+	if len(root.Branches) > 0 {
+		return root.Err.Error()
+	}
+	return root.Msg
+}
+
+// ParseLogfmt reads back an error rendered with the Logfmt report mode. Pairs that are not err_ fields are ignored so whole log lines can be parsed.
+// The parsed error holds the stamp trace with the kind and data on the outermost frame and the message and occurrence ID on the root cause.
+// It returns false when the line holds no err_ fields.
+func ParseLogfmt(line string) (*errx, bool) {
+	var stamps []lint
+	var id, kind, msg string
+	var data map[string]json.RawMessage
+	var rawData string
+	found := false
+
+	for key, val := range logfmtPairs(line) {
+		switch {
+		case key == logfmtStamps:
+			for _, s := range strings.Split(val, ",") {
+				if ts, err := strconv.Atoi(s); err == nil {
+					stamps = append(stamps, lint(ts))
+				}
+			}
+		case key == logfmtID:
+			id = val
+		case key == logfmtKind:
+			kind = val
+		case key == logfmtMsg:
+			msg = val
+		case key == logfmtData:
+			rawData = logfmtJSON(val)
+		case strings.HasPrefix(key, logfmtData+"."):
+			if data == nil {
+				data = make(map[string]json.RawMessage)
+			}
+			data[strings.TrimPrefix(key, logfmtData+".")] = json.RawMessage(logfmtJSON(val))
+		default:
+			continue
+		}
+		found = true
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	if data != nil {
+		if bs, err := json.Marshal(data); err == nil {
+			rawData = string(bs)
+		}
+	}
+
+	if len(stamps) == 0 && kind == "" && id == "" {
+		return stacksToErr([]stackFrame{{Msg: msg}}), true
+	} else if len(stamps) == 0 {
+		stamps = []lint{0}
+	}
+
+	frames := make([]stackFrame, 0, len(stamps))
+	for _, ts := range stamps {
+		frames = append(frames, stackFrame{IsStamped: true, Stamp: ts})
+	}
+	frames[0].Kind = newStackFrame("", "", kind, rawData, "").Kind
+	frames[len(frames)-1].ID = id
+	frames[len(frames)-1].Msg = msg
+
+	return stacksToErr(frames), true
+}
+
+func firstKindFrame(frames []Frame) (Frame, bool) {
+	for _, frame := range frames {
+		if frame.Kind != "" {
+			return frame, true
+		}
+		for _, branch := range frame.Branches {
+			if f, ok := firstKindFrame(branch); ok {
+				return f, true
+			}
+		}
+	}
+	return Frame{}, false
+}
+
+// writeLogfmtData writes object data as one pair per key and any other data as a single pair.
+func writeLogfmtData(b *strings.Builder, data string) {
+	var obj map[string]json.RawMessage
+	keys := make([]string, 0, 4)
+	if err := json.Unmarshal([]byte(data), &obj); err == nil {
+		for k := range obj {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 || slices.ContainsFunc(keys, func(k string) bool { return !isLogfmtKey(k) }) {
+		writeLogfmtPair(b, logfmtData, logfmtValue(data))
+		return
+	}
+
+	slices.Sort(keys)
+	for _, k := range keys {
+		writeLogfmtPair(b, logfmtData+"."+k, logfmtValue(string(obj[k])))
+	}
+}
+
+// logfmtValue turns a JSON value into its logfmt form. Strings are written bare unless they would be read back as another JSON value.
+func logfmtValue(raw string) string {
+	var s string
+	if err := json.Unmarshal([]byte(raw), &s); err != nil || json.Valid([]byte(s)) {
+		return raw
+	}
+	return s
+}
+
+// logfmtJSON is the inverse of logfmtValue.
+func logfmtJSON(val string) string {
+	if json.Valid([]byte(val)) {
+		return val
+	}
+	return toStr(val)
+}
+
+func writeLogfmtPair(b *strings.Builder, key, val string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if needsLogfmtQuote(val) {
+		b.WriteString(strconv.Quote(val))
+	} else {
+		b.WriteString(val)
+	}
+}
+
+func needsLogfmtQuote(val string) bool {
+	if val == "" {
+		return true
+	}
+	for _, r := range val {
+		if r == ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLogfmtKey(key string) bool {
+	return key != "" && !needsLogfmtQuote(key)
+}
+
+// logfmtPairs yields the key value pairs of a logfmt line. Keys without a value yield an empty value.
+func logfmtPairs(line string) func(yield func(string, string) bool) {
+	return func(yield func(string, string) bool) {
+		for line != "" {
+			line = strings.TrimLeft(line, " \t")
+			if line == "" {
+				return
+			}
+
+			end := strings.IndexAny(line, "= \t")
+			if end == -1 {
+				yield(line, "")
+				return
+			}
+			key := line[:end]
+			if line[end] != '=' {
+				line = line[end:]
+				if !yield(key, "") {
+					return
+				}
+				continue
+			}
+
+			line = line[end+1:]
+			var val string
+			if strings.HasPrefix(line, `"`) {
+				val, line = unquoteLogfmt(line)
+			} else if i := strings.IndexAny(line, " \t"); i != -1 {
+				val, line = line[:i], line[i:]
+			} else {
+				val, line = line, ""
+			}
+
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// unquoteLogfmt reads a quoted value from the start of s and returns it along with the rest of s.
+// An unterminated quote takes the rest of the line.
+func unquoteLogfmt(s string) (string, string) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			if v, err := strconv.Unquote(s[:i+1]); err == nil {
+				return v, s[i+1:]
+			}
+			return s[1:i], s[i+1:]
+		}
+	}
+	return s[1:], ""
+}
@@ -0,0 +1,103 @@
+package errx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogfmt(t *testing.T) {
+	user := DataKind[map[string]int]("notfound")
+
+	t.Run("Chain", func(t *testing.T) {
+		err := Wrap(3, Wrap(2, NewKind(1, user(map[string]int{"id": 42, "org": 7}), "user not found")))
+		assert.Equal(t, `err_stamps=3,2,1 err_kind=notfound err_data.id=42 err_data.org=7 err_msg="user not found"`, Report(err, Logfmt))
+	})
+
+	t.Run("ScalarData", func(t *testing.T) {
+		name := DataKind[string]("name")
+		assert.Equal(t, `err_stamps=1 err_kind=name err_data=bob err_msg=missing`, Report(NewKind(1, name("bob"), "missing"), Logfmt))
+		assert.Equal(t, `err_stamps=1 err_kind=name err_data="\"42\"" err_msg=missing`, Report(NewKind(1, name("42"), "missing"), Logfmt))
+		assert.Equal(t, `err_stamps=1 err_kind=name err_data="a b" err_msg=missing`, Report(NewKind(1, name("a b"), "missing"), Logfmt))
+	})
+
+	t.Run("Quoting", func(t *testing.T) {
+		err := New(1, "bad \"input\" a=b\nnext")
+		assert.Equal(t, `err_stamps=1 err_msg="bad \"input\" a=b\nnext"`, Report(err, Logfmt))
+		assert.Equal(t, `err_stamps=1 err_msg=""`, Report(New(1, ""), Logfmt))
+	})
+
+	t.Run("Foreign", func(t *testing.T) {
+		assert.Equal(t, `err_msg="plain error"`, Report(fmt.Errorf("plain error"), Logfmt))
+	})
+
+	t.Run("Joined", func(t *testing.T) {
+		err := JoinWrap(10, New(1, "e1"), NewKind(2, DefineKind("conflict"), "e2"))
+		assert.Contains(t, Report(err, Logfmt), "err_stamps=10,1,2 err_kind=conflict ")
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.Equal(t, "", Report(nil, Logfmt))
+	})
+}
+
+func TestParseLogfmt(t *testing.T) {
+	user := DataKind[map[string]int]("notfound")
+	name := DataKind[string]("name")
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		err := Wrap(3, Wrap(2, NewKind(1, user(map[string]int{"id": 42}), "user \"bob\" not found")))
+		parsed, ok := ParseLogfmt(Report(err, Logfmt))
+		assert.True(t, ok)
+		assert.Equal(t, []int{3, 2, 1}, parsed.Stamps())
+		assert.True(t, IsKind(parsed, DefineKind("notfound")))
+		assert.Equal(t, "user \"bob\" not found", CauseMessage(parsed))
+
+		data, ok := FindData(parsed, user)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]int{"id": 42}, *data)
+		assert.Equal(t, Report(err, Logfmt), Report(parsed, Logfmt))
+	})
+
+	t.Run("StringData", func(t *testing.T) {
+		for _, v := range []string{"bob", "42", "a b", "", "true"} {
+			parsed, ok := ParseLogfmt(Report(NewKind(1, name(v), "missing"), Logfmt))
+			assert.True(t, ok)
+			data, ok := FindData(parsed, name)
+			assert.True(t, ok, v)
+			assert.Equal(t, v, *data)
+		}
+	})
+
+	t.Run("LogLine", func(t *testing.T) {
+		parsed, ok := ParseLogfmt(`time=2024-01-01T00:00:00Z level=error debug msg="request failed" err_stamps=2,1 err_kind=timeout err_msg="deadline exceeded" path=/users`)
+		assert.True(t, ok)
+		assert.Equal(t, []int{2, 1}, parsed.Stamps())
+		assert.True(t, IsKind(parsed, DefineKind("timeout")))
+		assert.Equal(t, "deadline exceeded", CauseMessage(parsed))
+	})
+
+	t.Run("Unstamped", func(t *testing.T) {
+		parsed, ok := ParseLogfmt(`err_msg="plain error"`)
+		assert.True(t, ok)
+		assert.Empty(t, parsed.Stamps())
+		assert.Equal(t, "plain error", CauseMessage(parsed))
+
+		parsed, ok = ParseLogfmt(`err_kind=timeout err_msg=slow`)
+		assert.True(t, ok)
+		assert.True(t, IsKind(parsed, DefineKind("timeout")))
+	})
+
+	t.Run("Unterminated", func(t *testing.T) {
+		parsed, ok := ParseLogfmt(`err_stamps=1 err_msg="broken line`)
+		assert.True(t, ok)
+		assert.Equal(t, "broken line", CauseMessage(parsed))
+	})
+
+	t.Run("NoFields", func(t *testing.T) {
+		parsed, ok := ParseLogfmt(`level=info msg=ok`)
+		assert.False(t, ok)
+		assert.Nil(t, parsed)
+	})
+}
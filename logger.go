@@ -1,9 +1,25 @@
 package errx
 
+import "sync/atomic"
+
+// Logger writes an error to a log. Adapters such as errxzap and errxzerolog provide implementations.
 type Logger func(err error)
 
-var _logger Logger
+var _logger atomic.Pointer[Logger]
 
+// UseLogger configures the logger errors are written to by Log and by errx itself for errors it would otherwise discard,
+// such as the errors a Group drops past its SetMaxErrors limit. A nil logger disables logging.
 func UseLogger(logger Logger) {
-	_logger = logger
+	if logger == nil {
+		_logger.Store(nil)
+		return
+	}
+	_logger.Store(&logger)
+}
+
+// Log writes the error through the logger configured with UseLogger. It does nothing for nil errors or when no logger is configured.
+func Log(err error) {
+	if logger := _logger.Load(); logger != nil && err != nil {
+		(*logger)(err)
+	}
 }
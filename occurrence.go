@@ -0,0 +1,128 @@
+package errx
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _occurrenceIDs atomic.Bool
+
+// UseOccurrenceIDs enables assigning an occurrence ID to every root error, so one specific failure can be told apart from others with the same stamps.
+// The ID is preserved when the error is wrapped and is rendered next to the stamp of the frame holding it, as in [ts 1745397000 id 01JSHGW0R3DQ2C5Y8M9ZNTB4XA].
+func UseOccurrenceIDs(enabled bool) {
+	_occurrenceIDs.Store(enabled)
+}
+
+// OccurrenceID returns the occurrence ID of the error chain or an empty string when it has none.
+// Joined errors keep the occurrence IDs of their branches, which are not returned.
+func OccurrenceID(err error) string {
+	return framesOccurrenceID(Frames(err))
+}
+
+func framesOccurrenceID(frames []Frame) string {
+	for _, frame := range frames {
+		if frame.ID != "" {
+			return frame.ID
+		}
+	}
+	return ""
+}
+
+// occurrence holds the occurrence ID of a root error. The ID is generated the first time it is read,
+// so errors that are never logged or rendered do not pay for it.
+type occurrence struct {
+	once sync.Once
+	// ms is the creation time in unix milliseconds the ID is generated with
+	ms int64
+	id string
+}
+
+// parsedOccurrence returns an occurrence holding an ID read back from an error string.
+func parsedOccurrence(id string) *occurrence {
+	if id == "" {
+		return nil
+	}
+	return &occurrence{id: id}
+}
+
+// String returns the occurrence ID, generating it on first use. It returns an empty string for a nil occurrence.
+func (o *occurrence) String() string {
+	if o == nil {
+		return ""
+	}
+	o.once.Do(func() {
+		if o.id == "" {
+			o.id = newOccurrenceID(o.ms)
+		}
+	})
+	return o.id
+}
+
+// newOccurrenceID returns a ULID: 48 bits of unix milliseconds followed by 80 random bits written as 26 characters of Crockford's base32.
+// IDs sort by the time they were created.
+func newOccurrenceID(ms int64) string {
+	var r [10]byte
+	rand.Read(r[:])
+	hi := uint64(ms)<<16 | uint64(binary.BigEndian.Uint16(r[:2]))
+	lo := binary.BigEndian.Uint64(r[2:])
+	return formatOccurrenceID(hi, lo)
+}
+
+func formatOccurrenceID(hi, lo uint64) string {
+	var b [26]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// parseOccurrenceID returns the 128 bits of a ULID written by newOccurrenceID.
+func parseOccurrenceID(id string) (hi, lo uint64, ok bool) {
+	if len(id) != 26 || id[0] > '7' {
+		return 0, 0, false
+	}
+	for i := 0; i < len(id); i++ {
+		v := strings.IndexByte(crockfordAlphabet, id[i])
+		if v == -1 {
+			return 0, 0, false
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	return hi, lo, true
+}
+
+// rootOccurrence returns a new occurrence when IDs are enabled and the wrapped error does not already hold one.
+func rootOccurrence(wrapped error) *occurrence {
+	if !_occurrenceIDs.Load() || holdsOccurrence(wrapped) {
+		return nil
+	}
+	return &occurrence{ms: time.Now().UnixMilli()}
+}
+
+// holdsOccurrence reports whether the chain below err holds an occurrence ID.
+// It follows errx links and single error unwrapping without building frames, so wrapping stays cheap on deep chains.
+func holdsOccurrence(err error) bool {
+	for err != nil {
+		e, ok := err.(*errx)
+		if !ok {
+			err = errors.Unwrap(err)
+			continue
+		}
+		if e.id != nil {
+			return true
+		} else if e.errx != nil {
+			err = e.errx
+		} else {
+			err = e.err
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccurrenceID(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		err := Wrap(2, New(1, "user missing"))
+		assert.Equal(t, "", OccurrenceID(err))
+		assert.Equal(t, "[ts 2]; [ts 1] user missing", err.Error())
+	})
+
+	UseOccurrenceIDs(true)
+	defer UseOccurrenceIDs(false)
+
+	t.Run("Assigned at the root", func(t *testing.T) {
+		root := New(1, "user missing")
+		id := OccurrenceID(root)
+		assert.Regexp(t, `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`, id)
+		assert.NotEqual(t, id, OccurrenceID(New(1, "user missing")))
+
+		err := Wrapf(3, "lookup: %w", fmt.Errorf("db: %w", Wrap(2, root)))
+		assert.Equal(t, id, OccurrenceID(err))
+		assert.Equal(t, fmt.Sprintf("[ts 3]; lookup: db: [ts 2]; [ts 1 id %s] user missing", id), err.Error())
+
+		frames := Frames(err)
+		assert.Equal(t, id, frames[len(frames)-1].ID)
+		assert.Equal(t, "", frames[0].ID)
+	})
+
+	t.Run("Preserved by Wrapf and WithKind", func(t *testing.T) {
+		root := NewBuild(1, "user missing")
+		assert.Equal(t, OccurrenceID(root), OccurrenceID(Wrapf(2, "found %v", root)))
+		assert.Equal(t, OccurrenceID(root), OccurrenceID(root.WithKind(DefineKind("notfound"))))
+	})
+
+	t.Run("Foreign roots and sentinels", func(t *testing.T) {
+		err := Wrap(2, Wrap(1, errors.New("io failure")))
+		frames := Frames(err)
+		assert.NotEmpty(t, frames[1].ID)
+		assert.Equal(t, "", frames[0].ID)
+
+		sentinel := Sentinel(1745397000, DefineKind("notfound"), "user not found")
+		assert.Equal(t, "", OccurrenceID(sentinel))
+		first, second := Wrap(1, sentinel), Wrap(1, sentinel)
+		assert.NotEmpty(t, OccurrenceID(first))
+		assert.NotEqual(t, OccurrenceID(first), OccurrenceID(second))
+		assert.True(t, errors.Is(first, sentinel))
+	})
+
+	t.Run("Joined errors", func(t *testing.T) {
+		e1, e2 := New(1, "e1"), New(2, "e2")
+		err := JoinWrap(10, e1, e2)
+		assert.NotEmpty(t, OccurrenceID(err))
+		assert.NotEqual(t, OccurrenceID(e1), OccurrenceID(err))
+	})
+
+	t.Run("Parsed back", func(t *testing.T) {
+		err := WrapKind(2, DefineKind("notfound"), NewKind(1, DataKind[string]("name")("id 7"), "user id missing"))
+		id := OccurrenceID(err)
+
+		parsed := ParseStampedError(err.Error())
+		assert.Equal(t, id, OccurrenceID(parsed))
+		assert.Equal(t, []int{2, 1}, parsed.Stamps())
+		assert.Equal(t, "user id missing", CauseMessage(parsed))
+		assert.Equal(t, err.Error(), parsed.Error())
+
+		data, ok := FindData(parsed, DataKind[string]("name"))
+		assert.True(t, ok)
+		assert.Equal(t, "id 7", *data)
+	})
+
+	t.Run("LogValue", func(t *testing.T) {
+		err := Wrap(2, New(1, "user missing"))
+		attrs := err.(slog.LogValuer).LogValue().Group()
+		assert.Equal(t, "error_id", attrs[0].Key)
+		assert.Equal(t, OccurrenceID(err), attrs[0].Value.String())
+	})
+
+	t.Run("Logfmt", func(t *testing.T) {
+		err := Wrap(2, New(1, "user missing"))
+		line := Report(err, Logfmt)
+		assert.Equal(t, fmt.Sprintf(`err_stamps=2,1 err_id=%s err_msg="user missing"`, OccurrenceID(err)), line)
+
+		parsed, ok := ParseLogfmt(line)
+		assert.True(t, ok)
+		assert.Equal(t, OccurrenceID(err), OccurrenceID(parsed))
+	})
+
+	t.Run("Generated lazily", func(t *testing.T) {
+		root := New(1, "user missing").(*errx)
+		assert.NotNil(t, root.id)
+		assert.Equal(t, "", root.id.id)
+		assert.Nil(t, Wrap(3, Wrap(2, root)).(*errx).id)
+
+		id := OccurrenceID(root)
+		assert.Equal(t, id, root.id.id)
+		assert.Equal(t, id, OccurrenceID(root))
+
+		assert.Nil(t, Sentinel(1, DefineKind("notfound"), "user not found").(*errx).id)
+	})
+
+	t.Run("Public code", func(t *testing.T) {
+		err := Wrap(2, New(1, "user missing"))
+		code := PublicCode(err)
+
+		id, derr := DecodePublicCodeID(code)
+		assert.Nil(t, derr)
+		assert.Equal(t, OccurrenceID(err), id)
+
+		stamps, derr := DecodePublicCode(code)
+		assert.Nil(t, derr)
+		assert.Equal(t, []int{2, 1}, stamps)
+
+		id, derr = DecodePublicCodeID(PublicCode(Wrap(1, Sentinel(1745397000, DefineKind("notfound"), "user not found"))))
+		assert.Nil(t, derr)
+		assert.NotEmpty(t, id)
+	})
+}
@@ -8,6 +8,8 @@ type lexer struct {
 	len               int
 	openBracketsCount []int
 	list              []token
+	// inData reports whether the lexer is reading the data of a frame, where directives are not recognised
+	inData bool
 }
 
 func newLexer(input string) *lexer {
@@ -48,6 +50,7 @@ func (l *lexer) nextToken() token {
 		l.openBracketsCount = l.openBracketsCount[:len(l.openBracketsCount)-1]
 		if len(l.openBracketsCount) == 0 {
 			tok.typ = closeBrackets
+			l.inData = false
 		} else {
 			tok.typ = rBrackets
 		}
@@ -72,6 +75,18 @@ func (l *lexer) nextToken() token {
 			tok.literal = currStr
 			tok.typ = unknownToken
 		}
+	case 'i':
+		pretext := l.peekBehind(1)
+		postText := l.peekAhead(3)
+		if pretext == " " && postText == "id " && len(l.openBracketsCount) == 1 && !l.inData {
+			l.list = l.list[:len(l.list)-1] // remove pretext space from tree
+			tok.typ = idDirective
+			tok.literal = "id"
+			l.moveCursor(3)
+		} else {
+			tok.literal = currStr
+			tok.typ = unknownToken
+		}
 	case 'k':
 		pretext := l.peekBehind(1)
 		postText := l.peekAhead(5)
@@ -91,6 +106,7 @@ func (l *lexer) nextToken() token {
 			l.list = l.list[:len(l.list)-1] // remove pretext space from tree
 			tok.typ = dataDirective
 			tok.literal = "data"
+			l.inData = true
 			l.moveCursor(5)
 		} else {
 			tok.literal = currStr
@@ -169,6 +185,7 @@ const (
 	lBrackets
 	rBrackets
 	stampDirective
+	idDirective
 	kindDirective
 	dataDirective
 	wrapperDelimiter
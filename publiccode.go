@@ -0,0 +1,198 @@
+package errx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	crockfordCheck    = crockfordAlphabet + "*~$=U"
+	publicCodeGroup   = 4
+	publicCodeIDSize  = 16
+)
+
+var crockford = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+
+// ErrInvalidPublicCode is returned by DecodePublicCode for codes that are malformed, fail their checksum or carry an invalid signature.
+var ErrInvalidPublicCode = errors.New("invalid public code")
+
+var _publicCodeKey atomic.Pointer[cipher.AEAD]
+
+// UsePublicCodeKey configures the server key public codes are sealed with. Sealed codes are encrypted and authenticated with AES-256-GCM
+// under a random nonce, so they cannot be read, forged or enumerated without the key. Sealing adds 28 bytes, about 45 characters, to every code
+// and the same error gets a different code every time.
+// Codes issued before or without a key fail to decode once a key is set, so it should be called once at startup.
+func UsePublicCodeKey(key []byte) {
+	if len(key) == 0 {
+		_publicCodeKey.Store(nil)
+		return
+	}
+
+	// The AES key is derived from the server key so keys of any length can be used
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("errx public code"))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	_publicCodeKey.Store(&aead)
+}
+
+// PublicCode encodes the stamp trace of the error as a short reference code that is safe to show to customers, such as TJHW-B00D-RC7G3.
+// The code is written in Crockford's base32 and ends with a check symbol, so it can be read over the phone and mistyped characters are caught.
+// When the chain holds an occurrence ID the code carries it too, so a quoted code leads to the one failure it was issued for.
+// It returns an empty string when the error holds no stamps.
+func PublicCode(err error) string {
+	frames := Frames(err)
+	stamps := frameStamps(frames, nil)
+	if len(stamps) == 0 {
+		return ""
+	}
+
+	payload := make([]byte, 0, len(stamps)*5+publicCodeIDSize+1)
+	payload = binary.AppendUvarint(payload, uint64(len(stamps)))
+	prev := 0
+	for _, ts := range stamps {
+		payload = binary.AppendVarint(payload, int64(ts-prev))
+		prev = ts
+	}
+	if hi, lo, ok := parseOccurrenceID(framesOccurrenceID(frames)); ok {
+		payload = binary.BigEndian.AppendUint64(payload, hi)
+		payload = binary.BigEndian.AppendUint64(payload, lo)
+	}
+	if key := _publicCodeKey.Load(); key != nil {
+		payload = sealPublicCode(*key, payload)
+	}
+
+	encoded := crockford.EncodeToString(payload)
+	var b strings.Builder
+	b.Grow(len(encoded) + len(encoded)/publicCodeGroup + 1)
+	for i := 0; i < len(encoded); i += publicCodeGroup {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(encoded[i:min(i+publicCodeGroup, len(encoded))])
+	}
+	b.WriteByte(crockfordCheck[checkSymbol(payload)])
+	return b.String()
+}
+
+// DecodePublicCode returns the stamp trace encoded in a code issued by PublicCode.
+// Decoding is case insensitive, ignores dashes and spaces and reads I and L as 1 and O as 0.
+func DecodePublicCode(code string) ([]int, error) {
+	stamps, _, err := decodePublicCode(code)
+	return stamps, err
+}
+
+// DecodePublicCodeID returns the occurrence ID carried by a code issued by PublicCode or an empty string when the error had none.
+func DecodePublicCodeID(code string) (string, error) {
+	_, id, err := decodePublicCode(code)
+	return id, err
+}
+
+func decodePublicCode(code string) ([]int, string, error) {
+	code = normalizePublicCode(code)
+	if len(code) < 2 {
+		return nil, "", ErrInvalidPublicCode
+	}
+
+	check := strings.IndexByte(crockfordCheck, code[len(code)-1])
+	payload, err := crockford.DecodeString(code[:len(code)-1])
+	if err != nil || check == -1 {
+		return nil, "", ErrInvalidPublicCode
+	} else if checkSymbol(payload) != check {
+		return nil, "", fmt.Errorf("%w: checksum mismatch", ErrInvalidPublicCode)
+	}
+
+	if key := _publicCodeKey.Load(); key != nil {
+		if payload, err = openPublicCode(*key, payload); err != nil {
+			return nil, "", err
+		}
+	}
+
+	count, n := binary.Uvarint(payload)
+	if n <= 0 || count == 0 || count > uint64(len(payload)) {
+		return nil, "", ErrInvalidPublicCode
+	}
+	payload = payload[n:]
+
+	stamps := make([]int, 0, count)
+	prev := 0
+	for range count {
+		delta, n := binary.Varint(payload)
+		if n <= 0 {
+			return nil, "", ErrInvalidPublicCode
+		}
+		prev += int(delta)
+		stamps = append(stamps, prev)
+		payload = payload[n:]
+	}
+
+	switch len(payload) {
+	case 0:
+		return stamps, "", nil
+	case publicCodeIDSize:
+		return stamps, formatOccurrenceID(binary.BigEndian.Uint64(payload), binary.BigEndian.Uint64(payload[8:])), nil
+	default:
+		return nil, "", ErrInvalidPublicCode
+	}
+}
+
+func normalizePublicCode(code string) string {
+	var b strings.Builder
+	b.Grow(len(code))
+	for _, r := range strings.ToUpper(code) {
+		switch r {
+		case '-', ' ':
+		case 'I', 'L':
+			b.WriteByte('1')
+		case 'O':
+			b.WriteByte('0')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// checkSymbol returns the payload, read as a big endian number, modulo 37 as specified for Crockford's base32 check symbols.
+func checkSymbol(payload []byte) int {
+	sum := 0
+	for _, c := range payload {
+		sum = (sum*256 + int(c)) % 37
+	}
+	return sum
+}
+
+// sealPublicCode encrypts and authenticates the payload, prefixing it with the nonce it was sealed with.
+func sealPublicCode(aead cipher.AEAD, payload []byte) []byte {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(payload)+aead.Overhead())
+	rand.Read(nonce)
+	return aead.Seal(nonce, nonce, payload, nil)
+}
+
+// openPublicCode reverses sealPublicCode, rejecting payloads that were not sealed with the key or were altered.
+func openPublicCode(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) <= aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("%w: missing signature", ErrInvalidPublicCode)
+	}
+	payload, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidPublicCode)
+	}
+	return payload, nil
+}
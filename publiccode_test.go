@@ -0,0 +1,115 @@
+package errx
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicCode(t *testing.T) {
+	err := Wrap(1745397994, Wrap(1745397500, New(1745397000, "user not found")))
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		code := PublicCode(err)
+		assert.Regexp(t, `^[0-9A-HJKMNP-TV-Z]{4}(-[0-9A-HJKMNP-TV-Z]{1,4})*[0-9A-HJKMNP-TV-Z*~$=U]$`, code)
+
+		stamps, derr := DecodePublicCode(code)
+		assert.Nil(t, derr)
+		assert.Equal(t, []int{1745397994, 1745397500, 1745397000}, stamps)
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		code := PublicCode(err)
+		typed := strings.NewReplacer("-", " ", "0", "o", "1", "l").Replace(strings.ToLower(code))
+		stamps, derr := DecodePublicCode(typed)
+		assert.Nil(t, derr)
+		assert.Equal(t, []int{1745397994, 1745397500, 1745397000}, stamps)
+	})
+
+	t.Run("Typo", func(t *testing.T) {
+		code := []byte(PublicCode(err))
+		if code[0] == 'A' {
+			code[0] = 'B'
+		} else {
+			code[0] = 'A'
+		}
+		_, derr := DecodePublicCode(string(code))
+		assert.True(t, errors.Is(derr, ErrInvalidPublicCode))
+	})
+
+	t.Run("No occurrence ID", func(t *testing.T) {
+		id, derr := DecodePublicCodeID(PublicCode(err))
+		assert.Nil(t, derr)
+		assert.Equal(t, "", id)
+	})
+
+	t.Run("Joined", func(t *testing.T) {
+		stamps, derr := DecodePublicCode(PublicCode(JoinWrap(10, New(1, "e1"), New(2, "e2"))))
+		assert.Nil(t, derr)
+		assert.Equal(t, []int{10, 1, 2}, stamps)
+	})
+
+	t.Run("Unstamped", func(t *testing.T) {
+		assert.Equal(t, "", PublicCode(errors.New("plain")))
+		assert.Equal(t, "", PublicCode(nil))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		for _, code := range []string{"", "A", "!!!!", "ABCD-EFG#"} {
+			_, derr := DecodePublicCode(code)
+			assert.True(t, errors.Is(derr, ErrInvalidPublicCode), code)
+		}
+	})
+}
+
+func TestPublicCodeKey(t *testing.T) {
+	err := Wrap(1745397994, New(1745397000, "user not found"))
+	unsigned := PublicCode(err)
+
+	UsePublicCodeKey([]byte("server-key"))
+	defer UsePublicCodeKey(nil)
+
+	signed := PublicCode(err)
+	assert.NotEqual(t, unsigned, signed)
+
+	stamps, derr := DecodePublicCode(signed)
+	assert.Nil(t, derr)
+	assert.Equal(t, []int{1745397994, 1745397000}, stamps)
+
+	_, derr = DecodePublicCode(unsigned)
+	assert.True(t, errors.Is(derr, ErrInvalidPublicCode))
+
+	UsePublicCodeKey([]byte("other-key"))
+	_, derr = DecodePublicCode(signed)
+	assert.True(t, errors.Is(derr, ErrInvalidPublicCode))
+}
+
+func TestPublicCodeSealed(t *testing.T) {
+	err := Wrap(1745397994, New(1745397000, "user not found"))
+	stamp := binary.AppendVarint(nil, 1745397994)
+
+	UsePublicCodeKey([]byte("server-key"))
+	defer UsePublicCodeKey(nil)
+
+	code := normalizePublicCode(PublicCode(err))
+	payload, derr := crockford.DecodeString(code[:len(code)-1])
+	assert.Nil(t, derr)
+	assert.NotContains(t, string(payload), string(stamp))
+
+	// Every code is sealed under a fresh nonce, so codes of the same error share no keystream
+	again := PublicCode(err)
+	assert.NotEqual(t, code, normalizePublicCode(again))
+	stamps, derr := DecodePublicCode(again)
+	assert.Nil(t, derr)
+	assert.Equal(t, []int{1745397994, 1745397000}, stamps)
+
+	UsePublicCodeKey(nil)
+	stamps, _ = DecodePublicCode(PublicCode(err))
+	assert.Equal(t, []int{1745397994, 1745397000}, stamps)
+	stamps, _ = DecodePublicCode(code)
+	assert.NotContains(t, stamps, 1745397994)
+	assert.NotContains(t, stamps, 1745397000)
+}
@@ -0,0 +1,70 @@
+package errx
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// PanicError holds a value recovered from a panic.
+// It unwraps to the panic value when the value is an error.
+type PanicError struct {
+	Value any
+	// Stack is the stack of the panicking goroutine. It is only captured once enabled with CapturePanicStacks.
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+var _panicStacks atomic.Bool
+
+// CapturePanicStacks enables capturing the stack of the panicking goroutine in errors created by Recover and Safe.
+// The stack is held by the *PanicError in the chain, or by the *StampedPanic for panics raised by MustStamp and PanicStamp.
+func CapturePanicStacks(enabled bool) {
+	_panicStacks.Store(enabled)
+}
+
+// Recover converts a panic into an error stamped with the given timestamp and a panic kind, and assigns it to errp.
+// Panics raised by MustStamp and PanicStamp are converted back into their original stamped chain.
+// It must be deferred directly:
+//
+//	defer errx.Recover(1745397000, &err)
+func Recover(ts lint, errp *error) {
+	if v := recover(); v != nil {
+		*errp = fromPanic(ts, v)
+	}
+}
+
+// Safe calls fn and converts any panic raised by it into an error stamped with the given timestamp.
+func Safe(ts lint, fn func() error) (err error) {
+	defer Recover(ts, &err)
+	return fn()
+}
+
+func fromPanic(ts lint, v any) *errx {
+	var stack []byte
+	if _panicStacks.Load() {
+		stack = debug.Stack()
+	}
+
+	if sp, ok := v.(*StampedPanic); ok {
+		// The panic value is copied since it may be recovered more than once
+		return withKind(wrapErr(ts, &StampedPanic{Err: sp.Err, Stack: stack}), panicKind)
+	}
+
+	p := &PanicError{Value: v, Stack: stack}
+
+	if _, ok := v.(error); ok {
+		return withKind(wrapErr(ts, p), panicKind)
+	}
+	return withKind(wrapErr(ts, p), Panicked(fmt.Sprint(v)))
+}
@@ -0,0 +1,102 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("Panic value", func(t *testing.T) {
+		fn := func() (err error) {
+			defer Recover(1745397000, &err)
+			panic("boom")
+		}
+
+		err := fn()
+		assert.Equal(t, `[ts 1745397000 kind panic data "boom"]; panic: boom`, err.Error())
+		assert.True(t, IsDataKind(err, Panicked))
+		data, ok := FindData(err, Panicked)
+		assert.True(t, ok)
+		assert.Equal(t, "boom", *data)
+	})
+
+	t.Run("Panic error unwraps to the original", func(t *testing.T) {
+		orig := Wrap(2, New(1, "failure"))
+		fn := func() (err error) {
+			defer Recover(1745397000, &err)
+			panic(orig)
+		}
+
+		err := fn()
+		assert.True(t, errors.Is(err, orig))
+		assert.True(t, IsKind(err, panicKind))
+		assert.Equal(t, [][]int{{1745397000, 2, 1}}, StampPaths(err))
+		assert.Equal(t, "[ts 1745397000 kind panic]; panic: [ts 2]; [ts 1] failure", err.Error())
+	})
+
+	t.Run("No panic", func(t *testing.T) {
+		fn := func() (err error) {
+			defer Recover(1745397000, &err)
+			return New(1, "regular failure")
+		}
+		assert.Equal(t, "[ts 1] regular failure", fn().Error())
+	})
+
+	t.Run("Stack", func(t *testing.T) {
+		CapturePanicStacks(true)
+		defer CapturePanicStacks(false)
+
+		err := Safe(1745397000, func() error {
+			var m map[string]int
+			m["x"] = 1
+			return nil
+		})
+
+		var p *PanicError
+		assert.True(t, errors.As(err, &p))
+		assert.True(t, strings.Contains(string(p.Stack), "recover_test.go"))
+	})
+
+	t.Run("Stack of a stamped panic", func(t *testing.T) {
+		CapturePanicStacks(true)
+		defer CapturePanicStacks(false)
+
+		err := Safe(1745397000, func() error {
+			PanicStamp(2, New(1, "failure"))
+			return nil
+		})
+
+		var sp *StampedPanic
+		assert.True(t, errors.As(err, &sp))
+		assert.True(t, strings.Contains(string(sp.Stack), "recover_test.go"))
+		assert.Equal(t, "[ts 1745397000 kind panic]; [ts 2]; [ts 1] failure", err.Error())
+		assert.Equal(t, [][]int{{1745397000, 2, 1}}, StampPaths(err))
+	})
+
+	t.Run("One panic kind", func(t *testing.T) {
+		err := Safe(1745397000, func() error { panic(New(1, "failure")) })
+		assert.True(t, IsDataKind(err, Panicked))
+		_, ok := FindData(err, Panicked)
+		assert.False(t, ok)
+
+		meta, ok := KindInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, 500, meta.HTTPStatus)
+	})
+}
+
+func TestSafe(t *testing.T) {
+	assert.Nil(t, Safe(1, func() error { return nil }))
+	assert.Equal(t, "[ts 1] failure", Safe(1, func() error { return New(1, "failure") }).Error())
+
+	err := Safe(1745397000, func() error { panic(fmt.Errorf("wrapped: %w", errors.New("root"))) })
+	assert.Equal(t, "root", CauseMessage(err))
+
+	var p *PanicError
+	assert.True(t, errors.As(err, &p))
+	assert.Nil(t, p.Stack)
+}
@@ -0,0 +1,171 @@
+package errx
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// RedactMode decides how sensitive values are rendered.
+type RedactMode int
+
+const (
+	// Replaces sensitive values with ***
+	RedactMask RedactMode = iota + 1
+	// Replaces sensitive values with a short HMAC-SHA256 of the value keyed with the policy's Key so occurrences can still be correlated
+	RedactHash
+	// Removes sensitive values entirely
+	RedactDrop
+)
+
+const redactMask = "***"
+
+// RedactPolicy defines which error kinds are sensitive and how their values are rendered.
+// Kinds declared with Sensitive or SensitiveDataKind are always treated as sensitive.
+type RedactPolicy struct {
+	Mode RedactMode
+	// Kinds lists additional kind names treated as sensitive. This also covers kinds of parsed errors.
+	Kinds []string
+	// Messages redacts the messages of sensitive frames in addition to their data.
+	Messages bool
+	// Key is the secret RedactHash is keyed with. Without a key, hashes of low entropy values such as emails could be reversed by brute force.
+	// When empty a random key is generated per process, so hashes only correlate within one process.
+	Key []byte
+}
+
+var _redaction atomic.Pointer[RedactPolicy]
+
+// UseRedaction configures the redaction policy applied whenever errors are rendered by Error, Report, Frames or LogValue.
+// Errors cache their rendered string along with the policy it was rendered with, so strings cached before the call are rendered again.
+// Foreign wrappers such as fmt.Errorf keep the string they were created with, so the Error of a foreign wrapper created before the call still holds unredacted values.
+// Frames, Report and LogValue drop that text and render the wrapped errx errors with the policy instead.
+func UseRedaction(policy RedactPolicy) {
+	_redaction.Store(&policy)
+}
+
+func currentRedaction() *RedactPolicy {
+	return _redaction.Load()
+}
+
+// Redact returns a copy of the error chain with the policy applied to every errx frame, leaving the original error untouched.
+// Foreign errors wrapping errx errors are rebuilt from their own messages, so the copy is meant for rendering (Report, logging, responses) rather than matching with errors.As.
+// Sentinels still match the copy with errors.Is since they are matched by stamp.
+func Redact(err error, policy RedactPolicy) error {
+	if err == nil {
+		return nil
+	}
+	return redactFrames(walkFrames(err, nil), &policy)
+}
+
+// RedactReport renders the error chain like Report with the policy applied on top of the one configured with UseRedaction.
+func RedactReport(err error, mode ReportMode, policy RedactPolicy) string {
+	return Report(Redact(err, policy), mode)
+}
+
+// RedactLogValue returns the structured log value of the error chain like LogValue with the policy applied instead of the one configured with UseRedaction.
+func RedactLogValue(err error, policy RedactPolicy) slog.Value {
+	if err == nil {
+		return slog.GroupValue()
+	}
+	return logValue(walkFrames(err, &policy))
+}
+
+func redactFrames(frames []Frame, policy *RedactPolicy) error {
+	var rtn error
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		if len(frame.Branches) > 0 {
+			errs := make([]error, 0, len(frame.Branches))
+			for _, branch := range frame.Branches {
+				errs = append(errs, redactFrames(branch, policy))
+			}
+			rtn = Join(errs...)
+		} else if e, ok := frame.Err.(*errx); ok {
+			kind, msg := e.redacted(policy)
+			redacted := &errx{ts: e.ts, kind: kind, msg: msg, id: e.id, at: e.at, sentinel: e.sentinel, parsed: e.parsed}
+			if v, ok := rtn.(*errx); ok {
+				redacted.errx = v
+			} else {
+				redacted.err = rtn
+			}
+			rtn = redacted
+		} else if rtn == nil {
+			rtn = frame.Err
+		} else {
+			rtn = fmt.Errorf("%s %w", frame.Msg, rtn)
+		}
+	}
+	return rtn
+}
+
+func (p *RedactPolicy) isSensitive(kind Kind) bool {
+	return kind.sensitive || (kind.kind != "" && slices.Contains(p.Kinds, kind.kind))
+}
+
+// redacted returns the kind and message of the error with the policy applied.
+// The message of a Wrapf frame embeds the messages of the errors it wraps, so it is redacted when the kind of any of them is sensitive.
+func (e *errx) redacted(policy *RedactPolicy) (Kind, string) {
+	kind, msg := policy.apply(e.kind, e.msg)
+	if policy == nil || !policy.Messages || msg == "" || policy.isSensitive(e.kind) {
+		return kind, msg
+	}
+	for curr := e.errx; curr != nil; curr = curr.errx {
+		if curr.msg != "" && policy.isSensitive(curr.kind) {
+			return kind, policy.redact(msg)
+		}
+	}
+	return kind, msg
+}
+
+// apply returns the kind and message of a frame with the policy applied. A nil policy leaves both unchanged.
+func (p *RedactPolicy) apply(kind Kind, msg string) (Kind, string) {
+	if p == nil || !p.isSensitive(kind) {
+		return kind, msg
+	}
+
+	if kind.data.isSet {
+		if p.Mode == RedactDrop {
+			kind.data = dataValue{isSet: false}
+		} else {
+			kind.data = dataValue{isSet: true, valStr: p.redact(kind.data.String())}
+		}
+	}
+
+	if p.Messages && msg != "" {
+		msg = p.redact(msg)
+	}
+
+	return kind, msg
+}
+
+func (p *RedactPolicy) redact(val string) string {
+	switch p.Mode {
+	case RedactHash:
+		mac := hmac.New(sha256.New, p.hashKey())
+		mac.Write([]byte(val))
+		return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+	case RedactDrop:
+		return ""
+	default:
+		return redactMask
+	}
+}
+
+var _processRedactKey = sync.OnceValue(func() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+})
+
+func (p *RedactPolicy) hashKey() []byte {
+	if len(p.Key) > 0 {
+		return p.Key
+	}
+	return _processRedactKey()
+}
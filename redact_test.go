@@ -0,0 +1,164 @@
+package errx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	email := SensitiveDataKind[string]("email")
+	userID := DataKind[int]("user_id")
+
+	err := NewKind(1, email("john@doe.com"), "login failed for john@doe.com")
+	err = WrapKind(2, userID(42), err)
+	err = fmt.Errorf("handler: %w", err)
+	err = WrapKind(3, Sensitive(DefineKind("auth")), err)
+
+	t.Run("Mask", func(t *testing.T) {
+		res := Redact(err, RedactPolicy{Mode: RedactMask})
+		assert.Equal(t, "[ts 3 kind auth]; handler: [ts 2 kind user_id data 42]; [ts 1 kind email data ***] login failed for john@doe.com", res.Error())
+	})
+
+	t.Run("Mask messages", func(t *testing.T) {
+		res := Redact(err, RedactPolicy{Mode: RedactMask, Messages: true})
+		assert.Equal(t, "[ts 3 kind auth]; handler: [ts 2 kind user_id data 42]; [ts 1 kind email data ***] ***", res.Error())
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		res := Redact(err, RedactPolicy{Mode: RedactHash})
+		assert.NotContains(t, res.Error(), "john@doe.com\"")
+		assert.Contains(t, res.Error(), "kind email data hmac:")
+		assert.Equal(t, res.Error(), Redact(err, RedactPolicy{Mode: RedactHash}).Error())
+	})
+
+	t.Run("Hash is keyed", func(t *testing.T) {
+		keyA := Redact(err, RedactPolicy{Mode: RedactHash, Key: []byte("key a")}).Error()
+		keyB := Redact(err, RedactPolicy{Mode: RedactHash, Key: []byte("key b")}).Error()
+		assert.Equal(t, keyA, Redact(err, RedactPolicy{Mode: RedactHash, Key: []byte("key a")}).Error())
+		assert.NotEqual(t, keyA, keyB)
+
+		unkeyed := sha256.Sum256([]byte(`"john@doe.com"`))
+		assert.NotContains(t, keyA, hex.EncodeToString(unkeyed[:6]))
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		res := Redact(err, RedactPolicy{Mode: RedactDrop, Kinds: []string{"user_id"}})
+		assert.Equal(t, "[ts 3 kind auth]; handler: [ts 2 kind user_id]; [ts 1 kind email] login failed for john@doe.com", res.Error())
+	})
+
+	t.Run("Original error is untouched", func(t *testing.T) {
+		_ = Redact(err, RedactPolicy{Mode: RedactMask})
+		assert.Contains(t, err.Error(), `data "john@doe.com"`)
+		data, ok := FindData(err, email)
+		assert.True(t, ok)
+		assert.Equal(t, "john@doe.com", *data)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		res := Report(Redact(err, RedactPolicy{Mode: RedactMask}), Indent)
+		assert.Equal(t, "      [ts 1 kind email data ***] login failed for john@doe.com", strings.Split(res, ";\n")[3])
+	})
+
+	t.Run("RedactReport", func(t *testing.T) {
+		res := RedactReport(err, Indent, RedactPolicy{Mode: RedactMask})
+		assert.Equal(t, "      [ts 1 kind email data ***] login failed for john@doe.com", strings.Split(res, ";\n")[3])
+	})
+
+	t.Run("RedactLogValue", func(t *testing.T) {
+		value := RedactLogValue(err, RedactPolicy{Mode: RedactMask, Messages: true})
+		assert.Contains(t, value.String(), "error_data_str=***")
+		assert.Contains(t, value.String(), "error_msg=***")
+		assert.NotContains(t, value.String(), "john@doe.com")
+		assert.Equal(t, slog.GroupValue(), RedactLogValue(nil, RedactPolicy{}))
+	})
+
+	t.Run("Sentinels still match", func(t *testing.T) {
+		sentinel := Sentinel(10, email("admin@doe.com"), "admin locked")
+		res := Redact(Wrap(11, sentinel), RedactPolicy{Mode: RedactMask})
+		assert.True(t, errors.Is(res, sentinel))
+	})
+
+	t.Run("Nil error", func(t *testing.T) {
+		assert.Nil(t, Redact(nil, RedactPolicy{Mode: RedactMask}))
+	})
+}
+
+func TestUseRedaction(t *testing.T) {
+	UseRedaction(RedactPolicy{Mode: RedactMask, Kinds: []string{"token"}})
+	defer _redaction.Store(nil)
+
+	token := DataKind[string]("token")
+	err := NewKind(1, token("secret"), "bad token")
+	err = Wrap(2, err)
+
+	assert.Equal(t, "[ts 2]; [ts 1 kind token data ***] bad token", err.Error())
+	assert.Equal(t, "***", Frames(err)[1].Data)
+}
+
+func TestUseRedactionLogfmt(t *testing.T) {
+	UseRedaction(RedactPolicy{Mode: RedactMask, Kinds: []string{"token"}, Messages: true})
+	defer _redaction.Store(nil)
+
+	err := Wrap(2, NewKind(1, DataKind[string]("token")("secret"), "bad token secret"))
+	assert.Equal(t, `err_stamps=2,1 err_kind=token err_data=*** err_msg=***`, Report(err, Logfmt))
+
+	err = Wrapf(2, "login: %s", NewKind(1, Sensitive(DefineKind("auth")), "bad password hunter2"))
+	assert.NotContains(t, Report(err, Logfmt), "hunter2")
+}
+
+func TestUseRedactionInvalidatesCache(t *testing.T) {
+	defer _redaction.Store(nil)
+
+	token := DataKind[string]("token")
+	root := NewKind(1, token("secret"), "bad token")
+	err := Wrap(2, root)
+	assert.Equal(t, `[ts 2]; [ts 1 kind token data "secret"] bad token`, err.Error())
+
+	UseRedaction(RedactPolicy{Mode: RedactMask, Kinds: []string{"token"}})
+	assert.Equal(t, "[ts 2]; [ts 1 kind token data ***] bad token", err.Error())
+	assert.Equal(t, "[ts 1 kind token data ***] bad token", root.Error())
+
+	UseRedaction(RedactPolicy{Mode: RedactDrop, Kinds: []string{"token"}})
+	assert.Equal(t, "[ts 2]; [ts 1 kind token] bad token", err.Error())
+}
+
+func TestUseRedactionWrapf(t *testing.T) {
+	UseRedaction(RedactPolicy{Mode: RedactMask, Messages: true})
+	defer _redaction.Store(nil)
+
+	err := Wrapf(2, "login: %s", NewKind(1, Sensitive(DefineKind("auth")), "bad password hunter2"))
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.NotContains(t, Report(err, Indent), "hunter2")
+	assert.NotContains(t, LogValue(err).String(), "hunter2")
+	assert.Equal(t, "***", Frames(err)[0].Msg)
+
+	err = Wrapf(3, "retry: %s", Wrap(2, NewKind(1, Sensitive(DefineKind("auth")), "bad password hunter2")))
+	assert.NotContains(t, Report(err, Indent), "hunter2")
+
+	err = Wrapf(2, "login: %s", New(1, "user missing"))
+	assert.Equal(t, "login: user missing", Frames(err)[0].Msg)
+}
+
+func TestUseRedactionForeignWrapper(t *testing.T) {
+	defer _redaction.Store(nil)
+
+	token := DataKind[string]("token")
+	err := fmt.Errorf("handler: %w", Wrap(2, NewKind(1, token("secret"), "bad token")))
+
+	UseRedaction(RedactPolicy{Mode: RedactMask, Kinds: []string{"token"}})
+	// The foreign wrapper keeps the string it was created with
+	assert.Contains(t, err.Error(), `data "secret"`)
+
+	frames := Frames(err)
+	assert.Equal(t, "handler:", frames[0].Msg)
+	assert.Equal(t, "***", frames[2].Data)
+	assert.NotContains(t, Report(err, Indent), "secret")
+	assert.NotContains(t, LogValue(err).String(), "secret")
+}
@@ -0,0 +1,106 @@
+package errx
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Severity describes how serious an error kind is.
+type Severity int
+
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return ""
+}
+
+// KindMeta holds the metadata declared for an error kind.
+// It is the central place transport mapping, retries and documentation read from.
+type KindMeta struct {
+	// Name is the kind name the metadata was declared for
+	Name string
+	// HTTPStatus is the status code responses should use for the kind
+	HTTPStatus int
+	// GRPCCode is the numeric gRPC status code (google.golang.org/grpc/codes.Code) for the kind
+	GRPCCode uint32
+	// Retryable reports whether operations failing with the kind can be retried
+	Retryable bool
+	Severity  Severity
+	// Description is a public description of the kind that is safe to expose to clients
+	Description string
+}
+
+var _kinds sync.Map
+
+// registerKind records the metadata of a kind. The last declaration of a kind name wins.
+func registerKind(k string, meta []KindMeta) {
+	if len(meta) == 0 {
+		return
+	}
+	m := meta[0]
+	m.Name = k
+	_kinds.Store(k, m)
+}
+
+// LookupKind returns the metadata declared for the given kind name.
+func LookupKind(k string) (KindMeta, bool) {
+	if v, ok := _kinds.Load(k); ok {
+		return v.(KindMeta), true
+	}
+	return KindMeta{}, false
+}
+
+// RegisteredKinds returns the metadata of every declared kind sorted by name.
+func RegisteredKinds() []KindMeta {
+	rtn := make([]KindMeta, 0, 16)
+	_kinds.Range(func(_, v any) bool {
+		rtn = append(rtn, v.(KindMeta))
+		return true
+	})
+	slices.SortFunc(rtn, func(a, b KindMeta) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rtn
+}
+
+// KindInfo returns the metadata of the most relevant declared kind in the error chain.
+// The outermost frame whose kind has metadata wins, since outer layers refine the kinds of the errors they wrap.
+// Joined errors are searched branch by branch.
+func KindInfo(err error) (KindMeta, bool) {
+	return frameKindInfo(Frames(err))
+}
+
+func frameKindInfo(frames []Frame) (KindMeta, bool) {
+	for _, frame := range frames {
+		if frame.Kind != "" {
+			if meta, ok := LookupKind(frame.Kind); ok {
+				return meta, true
+			}
+		}
+		for _, branch := range frame.Branches {
+			if meta, ok := frameKindInfo(branch); ok {
+				return meta, true
+			}
+		}
+	}
+	return KindMeta{}, false
+}
@@ -0,0 +1,73 @@
+package errx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindInfo(t *testing.T) {
+	dbTimeout := DefineKind("registry_db_timeout", KindMeta{HTTPStatus: 504, GRPCCode: 4, Retryable: true, Severity: SeverityWarning})
+	userNotFound := DataKind[int]("registry_user_notfound", KindMeta{HTTPStatus: 404, GRPCCode: 5, Severity: SeverityInfo, Description: "The user does not exist"})
+	plain := DefineKind("registry_plain")
+
+	t.Run("Lookup", func(t *testing.T) {
+		meta, ok := LookupKind("registry_db_timeout")
+		assert.True(t, ok)
+		assert.Equal(t, "registry_db_timeout", meta.Name)
+		assert.Equal(t, 504, meta.HTTPStatus)
+		assert.True(t, meta.Retryable)
+		assert.Equal(t, "warning", meta.Severity.String())
+
+		_, ok = LookupKind("registry_plain")
+		assert.False(t, ok)
+
+		names := make([]string, 0)
+		for _, meta := range RegisteredKinds() {
+			names = append(names, meta.Name)
+		}
+		assert.Contains(t, names, "registry_db_timeout")
+		assert.Contains(t, names, "registry_user_notfound")
+		assert.NotContains(t, names, "registry_plain")
+	})
+
+	t.Run("Outermost declared kind wins", func(t *testing.T) {
+		err := NewKind(1, dbTimeout, "query timed out")
+		err = WrapKind(2, userNotFound(42), err)
+		err = WrapKind(3, plain, err)
+
+		meta, ok := KindInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, "registry_user_notfound", meta.Name)
+		assert.Equal(t, 404, meta.HTTPStatus)
+		assert.Equal(t, "The user does not exist", meta.Description)
+	})
+
+	t.Run("Foreign and parsed errors", func(t *testing.T) {
+		err := NewKind(1, dbTimeout, "query timed out")
+		err = Wrap(2, fmt.Errorf("repository: %w", err))
+
+		meta, ok := KindInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, "registry_db_timeout", meta.Name)
+
+		meta, ok = KindInfo(ParseStampedError(err.Error()))
+		assert.True(t, ok)
+		assert.Equal(t, uint32(4), meta.GRPCCode)
+	})
+
+	t.Run("Joined errors", func(t *testing.T) {
+		err := JoinWrap(1, New(2, "e1"), NewKind(3, dbTimeout, "e2"))
+		meta, ok := KindInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, "registry_db_timeout", meta.Name)
+	})
+
+	t.Run("No declared kind", func(t *testing.T) {
+		_, ok := KindInfo(NewKind(1, plain, "e1"))
+		assert.False(t, ok)
+		_, ok = KindInfo(nil)
+		assert.False(t, ok)
+	})
+}
@@ -10,74 +10,75 @@ const (
 	Reversed       ReportMode = 2
 	Indent         ReportMode = 3
 	ReversedIndent ReportMode = 4
+	// Logfmt renders the error as a single line of err_ prefixed logfmt pairs which ParseLogfmt reads back
+	Logfmt ReportMode = 5
 )
 
+// Report renders the error chain according to the given mode.
+// Joined errors (errors implementing Unwrap() []error) are rendered as a tree where each branch is reported separately.
 func Report(err error, mode ReportMode) string {
 	switch mode {
 	case Reversed:
-		frames := splitToFrames(err, 10)
-		reversed := make([]string, 0, len(frames))
-		for i := len(frames) - 1; i >= 0; i-- {
-			v := strings.TrimSpace(frames[i].err().Error())
-			if len(v) > 0 {
-				reversed = append(reversed, v)
+		paths := framePaths(Frames(err))
+		rendered := make([]string, 0, len(paths))
+		for _, frames := range paths {
+			reversed := make([]string, 0, len(frames))
+			for i := len(frames) - 1; i >= 0; i-- {
+				v := strings.TrimSpace(frames[i].String())
+				if len(v) > 0 {
+					reversed = append(reversed, v)
+				}
 			}
+			rendered = append(rendered, strings.Join(reversed, "; "))
 		}
 
-		return strings.Join(reversed, "; ")
+		return strings.Join(rendered, "\n")
 
 	case Indent:
-		frames := splitToFrames(err, 10)
-		indented := make([]string, 0, len(frames))
-		for idx, frame := range frames {
-			v := strings.TrimSpace(frame.err().Error())
-			if len(v) > 0 {
-				indented = append(indented, leftPad(v, idx*2))
-			}
-		}
-
-		return strings.Join(indented, ";\n")
+		return strings.Join(indentFrames(Frames(err), 0, nil), ";\n")
 
 	case ReversedIndent:
-		frames := splitToFrames(err, 10)
-		reversed := make([]string, 0, len(frames))
-		count := 0
-		for i := len(frames) - 1; i >= 0; i-- {
-			v := strings.TrimSpace(frames[i].err().Error())
-			if len(v) > 0 {
-				reversed = append(reversed, leftPad(v, count*2))
+		paths := framePaths(Frames(err))
+		rendered := make([]string, 0, len(paths))
+		for _, frames := range paths {
+			reversed := make([]string, 0, len(frames))
+			count := 0
+			for i := len(frames) - 1; i >= 0; i-- {
+				v := strings.TrimSpace(frames[i].String())
+				if len(v) > 0 {
+					reversed = append(reversed, leftPad(v, count*2))
+				}
+				count++
 			}
-			count++
+			rendered = append(rendered, strings.Join(reversed, ";\n"))
 		}
 
-		return strings.Join(reversed, ";\n")
+		return strings.Join(rendered, "\n")
+
+	case Logfmt:
+		return logfmt(err)
 	}
 
 	return err.Error()
 }
 
-func splitToFrames(err error, cap int) []stackFrame {
-	if cap == 0 {
-		cap = 10
-	}
-	frames := make([]stackFrame, 0, cap)
-	for err != nil {
-		uerr := Unwrap(err)
-		if uerr == nil {
-			fms := getStackFrames(strings.TrimSpace(err.Error()))
-			frames = append(frames, fms[0])
-		} else if v := strings.Split(err.Error(), uerr.Error()); len(v) > 0 {
-			if v[0] != "" {
-				fms := getStackFrames(strings.TrimSpace(v[0]))
-				frames = append(frames, fms[0])
-			} else {
-				err = Unwrap(err)
-				continue
+// indentFrames renders the frames top down, nesting each joined branch below the frames it was joined under.
+func indentFrames(frames []Frame, depth int, lines []string) []string {
+	for _, frame := range frames {
+		if len(frame.Branches) > 0 {
+			for _, branch := range frame.Branches {
+				lines = indentFrames(branch, depth, lines)
 			}
+			continue
+		}
+
+		v := strings.TrimSpace(frame.String())
+		if len(v) > 0 {
+			lines = append(lines, leftPad(v, depth*2))
 		}
-		err = uerr
+		depth++
 	}
-	return frames
+	return lines
 }
 
 func leftPad(s string, length int) string {
@@ -41,3 +41,37 @@ func TestReport(t *testing.T) {
 		assert.True(t, strings.HasPrefix(lines[2], "    "))
 	})
 }
+
+func TestReportJoined(t *testing.T) {
+	err := JoinWrap(10,
+		Wrap(11, New(1, "call one failed")),
+		Wrap(12, New(2, "call two failed")),
+		Wrap(13, New(3, "call three failed")),
+	)
+
+	t.Run("Indent", func(t *testing.T) {
+		res := Report(err, Indent)
+		lines := strings.Split(res, ";\n")
+		assert.Equal(t, []string{
+			"[ts 10]",
+			"  [ts 11]",
+			"    [ts 1] call one failed",
+			"  [ts 12]",
+			"    [ts 2] call two failed",
+			"  [ts 13]",
+			"    [ts 3] call three failed",
+		}, lines)
+	})
+
+	t.Run("Reversed", func(t *testing.T) {
+		res := Report(err, Reversed)
+		assert.Equal(t, "[ts 1] call one failed; [ts 11]; [ts 10]\n[ts 2] call two failed; [ts 12]; [ts 10]\n[ts 3] call three failed; [ts 13]; [ts 10]", res)
+	})
+
+	t.Run("ReversedIndent", func(t *testing.T) {
+		res := Report(err, ReversedIndent)
+		branches := strings.Split(res, "\n[")
+		assert.Len(t, branches, 3)
+		assert.True(t, strings.HasPrefix(branches[0], "[ts 1] call one failed;\n  [ts 11];\n    [ts 10]"))
+	})
+}
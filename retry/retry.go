@@ -0,0 +1,125 @@
+// Package retry retries operations failing with retryable errx kinds.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/michaelolof/errx"
+)
+
+// Clock abstracts waiting between attempts so retries can be tested without sleeping.
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Policy decides which errors are retried and how long to wait between attempts.
+// Zero values fall back to the documented defaults.
+type Policy struct {
+	// Stamp is the stamp of the error returned once retries stop
+	Stamp int
+	// MaxAttempts is the total number of attempts including the first one. Defaults to 3
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt. Defaults to 100ms
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts. Defaults to 30s
+	MaxDelay time.Duration
+	// Multiplier grows the wait after every attempt. Defaults to 2
+	Multiplier float64
+	// Jitter is the fraction (0 to 1) of every wait that is randomized. Zero disables jitter
+	Jitter float64
+	// Retryable reports whether an error should be retried.
+	// Defaults to the Retryable flag of the kind resolved by errx.KindInfo.
+	// Errors carrying errx.RetryAfter are always retried.
+	Retryable func(err error) bool
+	// Clock is used to wait between attempts. Defaults to the system clock
+	Clock Clock
+	// Rand returns the random number in [0, 1) used for jitter. Defaults to math/rand
+	Rand func() float64
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable, the attempts are exhausted or the context is done.
+// When fn does not succeed, every attempt's error is joined under the policy's stamp so the stamp trace of each attempt is kept.
+func Do(ctx context.Context, fn func(ctx context.Context) error, policy Policy) error {
+	p := policy.withDefaults()
+	errs := make([]error, 0, p.MaxAttempts)
+
+	for attempt := 0; attempt < p.MaxAttempts; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("attempt %d: %w", attempt+1, err))
+
+		if attempt == p.MaxAttempts-1 || !p.retryable(err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			errs = append(errs, ctx.Err())
+			return errx.BuildFrom(p.Stamp, errx.Join(errs...))
+		case <-p.Clock.After(p.delay(attempt, err)):
+		}
+	}
+
+	return errx.BuildFrom(p.Stamp, errx.Join(errs...))
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 100 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = 30 * time.Second
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	if p.Clock == nil {
+		p.Clock = realClock{}
+	}
+	if p.Rand == nil {
+		p.Rand = rand.Float64
+	}
+	return p
+}
+
+func (p Policy) retryable(err error) bool {
+	if errx.IsDataKind(err, errx.RetryAfter) {
+		return true
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	meta, ok := errx.KindInfo(err)
+	return ok && meta.Retryable
+}
+
+// delay returns the wait after the given zero based attempt.
+func (p Policy) delay(attempt int, err error) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempt))
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d -= d * math.Min(p.Jitter, 1) * p.Rand()
+	}
+
+	if after, ok := errx.FindData(err, errx.RetryAfter); ok {
+		d = math.Max(d, *after*float64(time.Second))
+	}
+	return time.Duration(d)
+}
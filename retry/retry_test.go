@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michaelolof/errx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	unavailable = errx.DefineKind("retry_test_unavailable", errx.KindMeta{Retryable: true})
+	invalid     = errx.DefineKind("retry_test_invalid")
+)
+
+type fakeClock struct {
+	waits []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func TestDo(t *testing.T) {
+	t.Run("Succeeds after retries", func(t *testing.T) {
+		clock := &fakeClock{}
+		calls := 0
+		err := Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errx.NewKind(1, unavailable, "service unavailable")
+			}
+			return nil
+		}, Policy{Stamp: 100, Clock: clock})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.waits)
+	})
+
+	t.Run("Exhausted attempts keep every chain", func(t *testing.T) {
+		clock := &fakeClock{}
+		calls := 0
+		err := Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return errx.BuildFrom(calls, errx.NewKind(10, unavailable, "service unavailable"))
+		}, Policy{Stamp: 100, MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 3 * time.Second, Clock: clock})
+
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, clock.waits)
+		assert.Equal(t, [][]int{{100, 1, 10}, {100, 2, 10}, {100, 3, 10}, {100, 4, 10}}, errx.StampPaths(err))
+		assert.True(t, errx.IsKind(errx.Split(errx.Unwrap(err))[3], unavailable))
+		assert.Contains(t, errx.Report(err, errx.Indent), "  attempt 4:")
+	})
+
+	t.Run("Errors that are not retryable stop immediately", func(t *testing.T) {
+		clock := &fakeClock{}
+		calls := 0
+		err := Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return errx.NewKind(1, invalid, "invalid input")
+		}, Policy{Stamp: 100, Clock: clock})
+
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, clock.waits)
+		assert.Equal(t, [][]int{{100, 1}}, errx.StampPaths(err))
+	})
+
+	t.Run("Custom retryable predicate", func(t *testing.T) {
+		calls := 0
+		_ = Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return errx.NewKind(1, invalid, "invalid input")
+		}, Policy{Stamp: 100, Clock: &fakeClock{}, Retryable: func(err error) bool {
+			return errx.IsKind(err, invalid)
+		}})
+
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("RetryAfter is honoured", func(t *testing.T) {
+		clock := &fakeClock{}
+		_ = Do(context.Background(), func(ctx context.Context) error {
+			return errx.NewKind(1, errx.RetryAfter(5), "rate limited")
+		}, Policy{Stamp: 100, MaxAttempts: 2, Clock: clock})
+
+		assert.Equal(t, []time.Duration{5 * time.Second}, clock.waits)
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		clock := &fakeClock{}
+		_ = Do(context.Background(), func(ctx context.Context) error {
+			return errx.NewKind(1, unavailable, "service unavailable")
+		}, Policy{Stamp: 100, MaxAttempts: 2, BaseDelay: time.Second, Jitter: 0.5, Clock: clock, Rand: func() float64 { return 0.5 }})
+
+		assert.Equal(t, []time.Duration{750 * time.Millisecond}, clock.waits)
+	})
+
+	t.Run("Context cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Do(ctx, func(ctx context.Context) error {
+			return errx.NewKind(1, unavailable, "service unavailable")
+		}, Policy{Stamp: 100, Clock: blockingClock{}})
+
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.True(t, strings.HasPrefix(err.Error(), "[ts 100]; attempt 1:"))
+	})
+}
+
+type blockingClock struct{}
+
+func (blockingClock) After(d time.Duration) <-chan time.Time {
+	return nil
+}
@@ -0,0 +1,106 @@
+package errx
+
+import (
+	"errors"
+	"slices"
+)
+
+// Translation is a rule of a Translator. It translates errors matching any of its conditions into a kind.
+type Translation struct {
+	ts      lint
+	kind    Kind
+	targets []error
+	kinds   []string
+	funcs   []func(error) bool
+}
+
+// Translate returns a rule translating errors into the given kind. Conditions are added with WhenIs, WhenKind and WhenFunc.
+// Translated errors are wrapped in a frame with the rule's stamp and kind, so logs show which rule translated them.
+func Translate(ts lint, kind Kind) *Translation {
+	return &Translation{ts: ts, kind: kind}
+}
+
+// WhenIs matches errors for which errors.Is reports true for any of the targets.
+func (t *Translation) WhenIs(targets ...error) *Translation {
+	t.targets = append(t.targets, targets...)
+	return t
+}
+
+// WhenKind matches errors holding any of the kinds, as reported by IsKind.
+func (t *Translation) WhenKind(kinds ...Kind) *Translation {
+	for _, kind := range kinds {
+		t.kinds = append(t.kinds, kind.kind)
+	}
+	return t
+}
+
+// WhenFunc matches errors for which match returns true.
+func (t *Translation) WhenFunc(match func(err error) bool) *Translation {
+	t.funcs = append(t.funcs, match)
+	return t
+}
+
+func (t *Translation) matches(err error) bool {
+	for _, target := range t.targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	for _, kind := range t.kinds {
+		if hasKind(err, kind) {
+			return true
+		}
+	}
+	for _, match := range t.funcs {
+		if match(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// Translator translates errors into the kinds of a layer when they cross into it, replacing ladders of errors.Is checks at every package boundary.
+//
+//	var fromDB = errx.NewTranslator(
+//		errx.Translate(1745397000, UserNotFound).WhenIs(sql.ErrNoRows).WhenKind(db.NoRows),
+//	)
+//
+//	return fromDB.Wrap(1745397010, err)
+type Translator struct {
+	rules []Translation
+}
+
+// NewTranslator returns a translator applying the first matching rule in the given order.
+// The rules are copied, so changing them afterwards does not affect the translator.
+func NewTranslator(rules ...*Translation) *Translator {
+	t := &Translator{rules: make([]Translation, 0, len(rules))}
+	for _, rule := range rules {
+		t.rules = append(t.rules, Translation{
+			ts:      rule.ts,
+			kind:    rule.kind,
+			targets: slices.Clone(rule.targets),
+			kinds:   slices.Clone(rule.kinds),
+			funcs:   slices.Clone(rule.funcs),
+		})
+	}
+	return t
+}
+
+// Wrap wraps the error with the given timestamp like Wrap, translating it with the first matching rule.
+// The original chain is kept as the cause of the translated frame, so Cause, errors.Is and errors.As still see it.
+func (t *Translator) Wrap(ts lint, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, rule := range t.rules {
+		if !rule.matches(err) {
+			continue
+		}
+		if rule.ts == 0 {
+			return withKind(wrapErr(ts, err), rule.kind)
+		}
+		return wrapErr(ts, withKind(wrapErr(rule.ts, err), rule.kind))
+	}
+	return Wrap(ts, err)
+}
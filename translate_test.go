@@ -0,0 +1,77 @@
+package errx
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslator(t *testing.T) {
+	dbNoRows := DefineKind("translate_db_norows")
+	dbTimeout := DefineKind("translate_db_timeout")
+	userNotFound := DefineKind("translate_user_notfound", KindMeta{HTTPStatus: 404})
+	userBusy := DefineKind("translate_user_busy")
+
+	tr := NewTranslator(
+		Translate(100, userNotFound).WhenIs(sql.ErrNoRows).WhenKind(dbNoRows),
+		Translate(0, userBusy).WhenKind(dbTimeout),
+		Translate(300, DefineKind("translate_short")).WhenFunc(func(err error) bool { return Contains(err, "short write") }),
+	)
+
+	t.Run("Matches errors.Is targets", func(t *testing.T) {
+		cause := fmt.Errorf("scan user: %w", sql.ErrNoRows)
+		err := tr.Wrap(2, cause)
+
+		assert.Equal(t, "[ts 2]; [ts 100 kind translate_user_notfound]; scan user: sql: no rows in result set", err.Error())
+		assert.True(t, IsKind(err, userNotFound))
+		assert.True(t, errors.Is(err, sql.ErrNoRows))
+		assert.Equal(t, cause, errors.Unwrap(errors.Unwrap(err)))
+
+		meta, ok := KindInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, 404, meta.HTTPStatus)
+	})
+
+	t.Run("Matches kinds", func(t *testing.T) {
+		err := tr.Wrap(2, Wrap(1, NewKind(0, dbNoRows, "no user row")))
+		assert.Equal(t, []int{2, 100, 1}, err.(*errx).Stamps())
+		assert.True(t, IsKind(err, userNotFound))
+		assert.True(t, IsKind(err, dbNoRows))
+	})
+
+	t.Run("Rules without a stamp tag the wrapping frame", func(t *testing.T) {
+		err := tr.Wrap(2, NewKind(1, dbTimeout, "query timed out"))
+		assert.Equal(t, "[ts 2 kind translate_user_busy]; [ts 1 kind translate_db_timeout] query timed out", err.Error())
+	})
+
+	t.Run("Matches funcs", func(t *testing.T) {
+		err := tr.Wrap(2, errors.New("short write"))
+		assert.True(t, IsKind(err, DefineKind("translate_short")))
+	})
+
+	t.Run("First rule wins", func(t *testing.T) {
+		err := tr.Wrap(2, Join(NewKind(1, dbTimeout, "slow"), sql.ErrNoRows))
+		assert.True(t, IsKind(err, userNotFound))
+		assert.False(t, IsKind(err, userBusy))
+	})
+
+	t.Run("Unmatched errors are wrapped", func(t *testing.T) {
+		err := tr.Wrap(2, errors.New("disk full"))
+		assert.Equal(t, "[ts 2]; disk full", err.Error())
+		assert.Nil(t, tr.Wrap(2, nil))
+	})
+
+	t.Run("Rules are copied", func(t *testing.T) {
+		rule := Translate(100, userNotFound).WhenIs(sql.ErrNoRows)
+		tr := NewTranslator(rule)
+		rule.WhenIs(sql.ErrConnDone).WhenKind(dbTimeout)
+		rule.targets[0] = sql.ErrTxDone
+
+		assert.True(t, IsKind(tr.Wrap(2, sql.ErrNoRows), userNotFound))
+		assert.False(t, IsKind(tr.Wrap(2, sql.ErrConnDone), userNotFound))
+		assert.False(t, IsKind(tr.Wrap(2, NewKind(1, dbTimeout, "slow")), userNotFound))
+	})
+}